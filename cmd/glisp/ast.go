@@ -6,11 +6,14 @@ import "strings"
 
 const DEF_VALUE = 0
 const DEF_FUNCTION = 1
+const DEF_PATTERN = 2 // params are the names bound by the pattern
 
 type astDef struct {
-	name   string
+	name   string   // the pattern for DEF_PATTERN
+	sym    *vSymbol // nil for DEF_PATTERN
 	typ    int
-	params []string
+	params []*vSymbol
+	keys   []*vSymbol // keyword parameters of a DEF_FUNCTION
 	body   ast
 }
 
@@ -31,7 +34,13 @@ type astLiteral struct {
 }
 
 type astId struct {
-	name string
+	name *vSymbol
+}
+
+// a primitive used by the expansion of a special form, looked up in the
+// core environment so that local or module bindings cannot capture it
+type astPrimitive struct {
+	name *vSymbol
 }
 
 type astIf struct {
@@ -50,8 +59,9 @@ type astQuote struct {
 }
 
 type astLetRec struct {
-	names  []string
-	params [][]string
+	names  []*vSymbol
+	params [][]*vSymbol
+	keys   [][]*vSymbol
 	bodies []ast
 	body   ast
 }
@@ -107,7 +117,26 @@ func (e *astId) evalPartial(env *Env) (*partialResult, error) {
 }
 
 func (e *astId) str() string {
-	return fmt.Sprintf("astId[%s]", e.name)
+	return fmt.Sprintf("astId[%s]", e.name.name)
+}
+
+func (e *astPrimitive) eval(env *Env) (Value, error) {
+	core := env
+	for core.previous != nil {
+		core = core.previous
+	}
+	if v, ok := core.bindings[e.name]; ok {
+		return v, nil
+	}
+	return nil, fmt.Errorf("no such primitive %s", e.name.name)
+}
+
+func (e *astPrimitive) evalPartial(env *Env) (*partialResult, error) {
+	return defaultEvalPartial(e, env)
+}
+
+func (e *astPrimitive) str() string {
+	return fmt.Sprintf("astPrimitive[%s]", e.name.name)
 }
 
 func (e *astIf) eval(env *Env) (Value, error) {
@@ -119,7 +148,7 @@ func (e *astIf) evalPartial(env *Env) (*partialResult, error) {
 	if err != nil {
 		return nil, err
 	}
-	if c.isTrue() {
+	if firstValue(c).isTrue() {
 		return &partialResult{e.thn, env, nil}, nil
 	} else {
 		return &partialResult{e.els, env, nil}, nil
@@ -139,18 +168,20 @@ func (e *astApply) evalPartial(env *Env) (*partialResult, error) {
 	if err != nil {
 		return nil, err
 	}
+	f = firstValue(f)
 	args := make([]Value, len(e.args))
 	for i := range args {
-		args[i], err = e.args[i].eval(env)
+		v, err := e.args[i].eval(env)
 		if err != nil {
 			return nil, err
 		}
+		args[i] = firstValue(v)
 	}
 	if ff, ok := f.(*vFunction); ok {
-		if len(ff.params) != len(args) {
-			return nil, fmt.Errorf("Wrong number of arguments to application to %s", ff.str())
+		newEnv, err := ff.bind(args)
+		if err != nil {
+			return nil, err
 		}
-		newEnv := layer(ff.env, ff.params, args)
 		return &partialResult{ff.body, newEnv, nil}, nil
 	}
 	v, err := f.apply(args)
@@ -185,14 +216,14 @@ func (e *astLetRec) eval(env *Env) (Value, error) {
 }
 
 func (e *astLetRec) evalPartial(env *Env) (*partialResult, error) {
-	if len(e.names) != len(e.params) || len(e.names) != len(e.bodies) {
-		return nil, errors.New("malformed letrec (names, params, bodies)")
+	if len(e.names) != len(e.params) || len(e.names) != len(e.keys) || len(e.names) != len(e.bodies) {
+		return nil, errors.New("malformed letrec (names, params, keys, bodies)")
 	}
 	// create the environment that we'll share across the definitions
 	// all names initially allocated #nil
 	newEnv := layer(env, e.names, nil)
 	for i, name := range e.names {
-		update(newEnv, name, &vFunction{e.params[i], e.bodies[i], newEnv})
+		update(newEnv, name, &vFunction{e.params[i], e.keys[i], e.bodies[i], newEnv})
 	}
 	return &partialResult{e.body, newEnv, nil}, nil
 }
@@ -200,8 +231,8 @@ func (e *astLetRec) evalPartial(env *Env) (*partialResult, error) {
 func (e *astLetRec) str() string {
 	bindings := make([]string, len(e.names))
 	for i := range e.names {
-		params := strings.Join(e.params[i], " ")
-		bindings[i] = fmt.Sprintf("[%s [%s] %s]", e.names[i], params, e.bodies[i].str())
+		params := paramsString(e.params[i], e.keys[i])
+		bindings[i] = fmt.Sprintf("[%s [%s] %s]", e.names[i].name, params, e.bodies[i].str())
 	}
 	return fmt.Sprintf("astLetRec[%s %s]", strings.Join(bindings, " "), e.body.str())
 }
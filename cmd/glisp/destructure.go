@@ -0,0 +1,234 @@
+package main
+
+import "fmt"
+
+// Bindings in let, let*, fn parameters and def may be patterns that take
+// a value apart instead of plain names:
+//
+//   (a b . rest)        a list of at least two elements
+//   [a b]               an array or vector of exactly two elements
+//   {:keys (host port)} a dict or hash-map with keys :host and :port
+//                       (or the symbols or strings host and port)
+//   {p key}             a dict or hash-map, matching p against the
+//                       value under key
+//
+// Patterns nest, and _ matches anything without binding it. Since
+// (def (f x) body) defines a function, def takes a list pattern quoted:
+//
+//   (def '(a b . rest) xs)
+//
+// A pattern binding is parsed into a fresh name, and the body is wrapped
+// so that (destructure 'pattern value) hands the bound values over to it
+// as multiple values.
+
+const PATTERN_IGNORE = "_"
+
+func patternNames(pattern Value) ([]*vSymbol, error) {
+	names := []*vSymbol{}
+	var walk func(p Value) error
+	add := func(name *vSymbol) error {
+		if name.name == PATTERN_IGNORE {
+			return nil
+		}
+		for _, other := range names {
+			if name == other {
+				return fmt.Errorf("duplicate name %s in pattern %s", name.name, pattern.Display())
+			}
+		}
+		names = append(names, name)
+		return nil
+	}
+	walk = func(p Value) error {
+		if name, ok := p.(*vSymbol); ok {
+			return add(name)
+		}
+		if v, ok := p.(*vVector); ok {
+			for _, item := range v.content.toSlice() {
+				if err := walk(item); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		if m, ok := p.(*vMap); ok {
+			for _, e := range m.content.entries() {
+				if isKeyword(e.key, "keys") {
+					keys, err := parseSymbols(e.value)
+					if err != nil {
+						return fmt.Errorf("expected {:keys (name ...)} in pattern %s", pattern.Display())
+					}
+					for _, key := range keys {
+						if err := add(key); err != nil {
+							return err
+						}
+					}
+					continue
+				}
+				if _, ok := hashKey(e.value); !ok {
+					return fmt.Errorf("key %s not hashable in pattern %s", e.value.Display(), pattern.Display())
+				}
+				if err := walk(e.key); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		if _, _, ok := p.asCons(); ok || p.isEmpty() {
+			current := p
+			for head, next, ok := p.asCons(); ok; head, next, ok = next.asCons() {
+				if err := walk(head); err != nil {
+					return err
+				}
+				current = next
+			}
+			if current.isEmpty() {
+				return nil
+			}
+			// the pattern after a dot takes the rest of the list
+			return walk(current)
+		}
+		return fmt.Errorf("cannot use %s in pattern %s", p.Display(), pattern.Display())
+	}
+	if err := walk(pattern); err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+func parsePattern(sexp Value) (*vSymbol, Value, error) {
+	// a plain name, or a fresh name for the value matched by a pattern
+	if name, ok := sexp.(*vSymbol); ok {
+		return name, nil, nil
+	}
+	pattern, err := literalValue(sexp)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err := patternNames(pattern); err != nil {
+		return nil, nil, err
+	}
+	return fresh("__pattern"), pattern, nil
+}
+
+func makeDestructure(pattern Value, value ast) ast {
+	return &astApply{&astPrimitive{intern("destructure")}, []ast{&astQuote{pattern}, value}}
+}
+
+func destructurePatterns(params []*vSymbol, patterns []Value, body ast) ast {
+	// wrap body so that it sees the names bound by the patterns
+	for i := len(params) - 1; i >= 0; i-- {
+		if patterns[i] == nil {
+			continue
+		}
+		names, _ := patternNames(patterns[i]) // checked when parsed
+		body = makeCallWithValues(names, makeDestructure(patterns[i], &astId{params[i]}), body)
+	}
+	return body
+}
+
+func destructure(pattern Value, v Value) ([]Value, error) {
+	// the values bound by the pattern, in the order of patternNames
+	result := []Value{}
+	var match func(p Value, v Value) error
+	mismatch := func(p Value, v Value, format string, args ...interface{}) error {
+		return fmt.Errorf("pattern %s - %s in %s", p.Display(), fmt.Sprintf(format, args...), v.Display())
+	}
+	match = func(p Value, v Value) error {
+		if name, ok := p.asSymbol(); ok {
+			if name != PATTERN_IGNORE {
+				result = append(result, v)
+			}
+			return nil
+		}
+		if pv, ok := p.(*vVector); ok {
+			var items []Value
+			if vv, ok := v.(*vVector); ok {
+				items = vv.content.toSlice()
+			} else if content, ok := v.asArray(); ok {
+				items = content
+			} else {
+				return mismatch(p, v, "expected an array or vector")
+			}
+			if len(items) != pv.content.count {
+				return mismatch(p, v, "expected %d elements but got %d", pv.content.count, len(items))
+			}
+			for i, item := range pv.content.toSlice() {
+				if err := match(item, items[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		if pm, ok := p.(*vMap); ok {
+			var get func(Value) (Value, bool, error)
+			if content, ok := v.asDict(); ok {
+				get = content.get
+			} else if m, ok := v.(*vMap); ok {
+				get = m.content.get
+			} else {
+				return mismatch(p, v, "expected a dict or hash-map")
+			}
+			for _, e := range pm.content.entries() {
+				if isKeyword(e.key, "keys") {
+					keys, _ := parseSymbols(e.value)
+					for _, key := range keys {
+						if key.name == PATTERN_IGNORE {
+							continue
+						}
+						var item Value
+						found := false
+						for _, k := range []Value{NewKeyword(key.name), key, NewString(key.name)} {
+							if item, found, _ = get(k); found {
+								break
+							}
+						}
+						if !found {
+							return mismatch(p, v, "missing key %s", key.name)
+						}
+						result = append(result, item)
+					}
+					continue
+				}
+				item, found, err := get(e.value)
+				if err != nil {
+					return err
+				}
+				if !found {
+					return mismatch(p, v, "missing key %s", e.value.Display())
+				}
+				if err := match(e.key, item); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		current, rest := p, v
+		for head, next, ok := p.asCons(); ok; head, next, ok = next.asCons() {
+			item, restNext, ok := rest.asCons()
+			if !ok {
+				if rest.isEmpty() {
+					return mismatch(p, v, "too few elements")
+				}
+				return mismatch(p, v, "expected a list")
+			}
+			if err := match(head, item); err != nil {
+				return err
+			}
+			current, rest = next, restNext
+		}
+		if !current.isEmpty() {
+			return match(current, rest)
+		}
+		if rest.isEmpty() {
+			return nil
+		}
+		if _, _, ok := rest.asCons(); ok {
+			return mismatch(p, v, "too many elements")
+		}
+		return mismatch(p, v, "expected a list")
+	}
+	if err := match(pattern, v); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
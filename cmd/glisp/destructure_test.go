@@ -0,0 +1,34 @@
+package main
+
+import "testing"
+
+func TestDestructuringBindings(t *testing.T) {
+	checkEval(t, `(let (((a b . rest) '(1 2 3 4)) ({:keys (host port)} {:host "h" :port 80})) (list a b rest host port))`, `(1 2 (3 4) "h" 80)`)
+	checkEval(t, `(let* (([a b] [1 2]) (c (+ a b))) c)`, `3`)
+	checkEval(t, `((fn ((a _) {x :k}) (list a x)) '(1 2) (dict '(:k 3)))`, `(1 3)`)
+	checkEvalError(t, `(let (((a b) '(1))) a)`, `pattern (a b) - too few elements in (1)`)
+	checkEvalError(t, `(let (([a b] '(1 2))) a)`, `expected an array or vector`)
+	checkEvalError(t, `(let (((a a) '(1 2))) a)`, `duplicate name a`)
+}
+
+func TestDestructureHygiene(t *testing.T) {
+	checkEval(t, `(let ((destructure 5)) (let (((a b) '(1 2))) (list a b)))`, `(1 2)`)
+}
+
+func TestDefPatterns(t *testing.T) {
+	checkEval(t, `(def [p q] [10 20]) (+ p q)`, `30`)
+	checkEval(t, `(def '(p q . r) (list 10 20 30)) (list p q r)`, `(10 20 (30))`)
+	checkEval(t, `(def '((p) [q]) (list (list 1) [2])) (+ p q)`, `3`)
+	checkEval(t, `(def (p q) (list 10 20)) (p 1)`, `(10 20)`)
+	checkEvalError(t, `(def ((p q)) (list 10 20))`, `to destructure a list`)
+	for src, declared := range map[string]string{
+		`(def [p q] [10 20])`:       "[p q]",
+		`(def '(p q) (list 10 20))`: "(p q)",
+	} {
+		forms, _ := readAll(src)
+		e := NewEngine()
+		if _, name, err := e.evalTop(e.env, forms[0]); err != nil || name != declared {
+			t.Errorf("%s - expected to declare %s but got %q", src, declared, name)
+		}
+	}
+}
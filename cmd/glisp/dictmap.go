@@ -0,0 +1,248 @@
+package main
+
+import "fmt"
+import "sort"
+import "strconv"
+import "strings"
+
+// Dict keys are any immutable value: integers, strings, symbols, keywords,
+// booleans, characters, and lists, vectors, hash-maps and sets of those.
+// A key is identified by a canonical string built from its structure, so
+// equal keys land in the same slot. Entries are kept in insertion order
+// for display and iteration.
+
+func hashKey(v Value) (string, bool) {
+	var b strings.Builder
+	if !writeHashKey(&b, v) {
+		return "", false
+	}
+	return b.String(), true
+}
+
+func writeHashKey(b *strings.Builder, v Value) bool {
+	if i, ok := v.asInteger(); ok {
+		b.WriteString("i")
+		b.WriteString(strconv.Itoa(i))
+		return true
+	}
+	if s, ok := v.asString(); ok {
+		b.WriteString("s")
+		b.WriteString(strconv.Quote(s))
+		return true
+	}
+	if s, ok := v.asSymbol(); ok {
+		b.WriteString("y")
+		b.WriteString(strconv.Quote(s))
+		if sym, ok := v.(*vSymbol); ok && sym.id != 0 {
+			// a gensym differs from the interned symbol of the same name
+			b.WriteString("#")
+			b.WriteString(strconv.Itoa(sym.id))
+		}
+		return true
+	}
+	if k, ok := v.asKeyword(); ok {
+		b.WriteString("k")
+		b.WriteString(strconv.Quote(k))
+		return true
+	}
+	if bv, ok := v.asBoolean(); ok {
+		if bv {
+			b.WriteString("#t")
+		} else {
+			b.WriteString("#f")
+		}
+		return true
+	}
+	if c, ok := v.asChar(); ok {
+		b.WriteString("c")
+		b.WriteString(strconv.Itoa(int(c)))
+		return true
+	}
+	if v.isEmpty() {
+		b.WriteString("()")
+		return true
+	}
+	if _, _, ok := v.asCons(); ok {
+		b.WriteString("(")
+		current := v
+		for head, next, ok := v.asCons(); ok; head, next, ok = next.asCons() {
+			if !writeHashKey(b, head) {
+				return false
+			}
+			b.WriteString(" ")
+			current = next
+		}
+		if !current.isEmpty() {
+			b.WriteString(". ")
+			if !writeHashKey(b, current) {
+				return false
+			}
+		}
+		b.WriteString(")")
+		return true
+	}
+	if p, ok := v.(*vVector); ok {
+		b.WriteString("[")
+		for i := 0; i < p.content.count; i++ {
+			if !writeHashKey(b, p.content.nth(i)) {
+				return false
+			}
+			b.WriteString(" ")
+		}
+		b.WriteString("]")
+		return true
+	}
+	if m, ok := v.(*vMap); ok {
+		// entries sorted, since maps equal as values may be built differently
+		entries := []string{}
+		for _, e := range m.content.entries() {
+			value, ok := hashKey(e.value)
+			if !ok {
+				return false
+			}
+			entries = append(entries, e.id+" "+value+" ")
+		}
+		sort.Strings(entries)
+		b.WriteString("{")
+		b.WriteString(strings.Join(entries, ""))
+		b.WriteString("}")
+		return true
+	}
+	if s, ok := v.(*vSet); ok {
+		ids := []string{}
+		for _, e := range s.content.entries() {
+			ids = append(ids, e.id+" ")
+		}
+		sort.Strings(ids)
+		b.WriteString("#{")
+		b.WriteString(strings.Join(ids, ""))
+		b.WriteString("}")
+		return true
+	}
+	if v.isNil() {
+		b.WriteString("nil")
+		return true
+	}
+	return false
+}
+
+type dictEntry struct {
+	id      string
+	key     Value
+	value   Value
+	removed bool
+}
+
+type dictMap struct {
+	items   []dictEntry // in insertion order, with removed entries left in place
+	index   map[string]int
+	removed int
+}
+
+func newDictMap() *dictMap {
+	return &dictMap{items: []dictEntry{}, index: map[string]int{}}
+}
+
+func dictKeyError(key Value) error {
+	return fmt.Errorf("key not hashable %s", key.Display())
+}
+
+func (m *dictMap) size() int {
+	return len(m.index)
+}
+
+func (m *dictMap) entries() []dictEntry {
+	// removed entries are dropped here rather than on every remove, so
+	// that removing is constant time
+	if m.removed == 0 {
+		return m.items
+	}
+	live := make([]dictEntry, 0, len(m.index))
+	for _, entry := range m.items {
+		if !entry.removed {
+			m.index[entry.id] = len(live)
+			live = append(live, entry)
+		}
+	}
+	m.items = live
+	m.removed = 0
+	return m.items
+}
+
+func (m *dictMap) get(key Value) (Value, bool, error) {
+	h, ok := hashKey(key)
+	if !ok {
+		return nil, false, dictKeyError(key)
+	}
+	i, ok := m.index[h]
+	if !ok {
+		return nil, false, nil
+	}
+	return m.items[i].value, true, nil
+}
+
+func (m *dictMap) set(key Value, value Value) error {
+	// an existing key keeps its position
+	h, ok := hashKey(key)
+	if !ok {
+		return dictKeyError(key)
+	}
+	if i, ok := m.index[h]; ok {
+		m.items[i].value = value
+		return nil
+	}
+	m.index[h] = len(m.items)
+	m.items = append(m.items, dictEntry{id: h, key: key, value: value})
+	return nil
+}
+
+func (m *dictMap) remove(key Value) error {
+	h, ok := hashKey(key)
+	if !ok {
+		return dictKeyError(key)
+	}
+	i, ok := m.index[h]
+	if !ok {
+		return nil
+	}
+	delete(m.index, h)
+	m.items[i] = dictEntry{removed: true}
+	m.removed += 1
+	return nil
+}
+
+func (m *dictMap) keys() []Value {
+	entries := m.entries()
+	result := make([]Value, len(entries))
+	for i, entry := range entries {
+		result[i] = entry.key
+	}
+	return result
+}
+
+func (m *dictMap) values() []Value {
+	entries := m.entries()
+	result := make([]Value, len(entries))
+	for i, entry := range entries {
+		result[i] = entry.value
+	}
+	return result
+}
+
+func (m *dictMap) pairs() []Value {
+	entries := m.entries()
+	result := make([]Value, len(entries))
+	for i, entry := range entries {
+		result[i] = listFromSlice([]Value{entry.key, entry.value})
+	}
+	return result
+}
+
+func symbolDict(names []string, values []Value) *vDict {
+	// for primitives that report records with fixed symbol keys
+	m := newDictMap()
+	for i, name := range names {
+		m.set(NewSymbol(name), values[i])
+	}
+	return &vDict{m}
+}
@@ -0,0 +1,64 @@
+package main
+
+import "testing"
+
+func TestHashKeysAreDistinct(t *testing.T) {
+	keys := []string{
+		`(list (string->symbol "a yb"))`,
+		`'(a b)`,
+		`(list (string->keyword "a kb"))`,
+		`'(:a :b)`,
+		`(string->symbol "a")`,
+		`"a"`,
+		`:a`,
+		`'(1 2)`,
+		`'(12)`,
+		`[1 2]`,
+		`{1 2}`,
+		`#{1 2}`,
+		`(hash-map (string->symbol "a b") 1)`,
+		`(hash-map 'a (string->symbol "b 1"))`,
+		`'(1 . 2)`,
+	}
+	e := NewEngine()
+	seen := map[string]string{}
+	for _, src := range keys {
+		v, err := evalSource(e, src)
+		if err != nil {
+			t.Fatalf("%s - %s", src, err.Error())
+		}
+		id, ok := hashKey(v)
+		if !ok {
+			t.Fatalf("%s - not hashable", src)
+		}
+		if other, ok := seen[id]; ok {
+			t.Errorf("%s and %s have the same hash key %s", src, other, id)
+		}
+		seen[id] = src
+	}
+}
+
+func TestDictKeyCollision(t *testing.T) {
+	checkEval(t, `(length (dict-keys (dict (list (list (string->symbol "a yb")) 1) (list (list 'a 'b) 2))))`, `2`)
+}
+
+func TestDictRemoveKeepsOrder(t *testing.T) {
+	m := newDictMap()
+	for i := 0; i < 10; i++ {
+		m.set(NewInteger(i), NewInteger(i*i))
+	}
+	for i := 0; i < 10; i += 3 {
+		m.remove(NewInteger(i))
+	}
+	m.set(NewInteger(3), NewString("back"))
+	if v, ok, _ := m.get(NewInteger(4)); !ok || v.Display() != "16" {
+		t.Errorf("expected 4 to map to 16")
+	}
+	if m.size() != 7 {
+		t.Errorf("expected 7 entries but got %d", m.size())
+	}
+	if keys := listFromSlice(m.keys()).Display(); keys != "(1 2 4 5 7 8 3)" {
+		t.Errorf("expected keys (1 2 4 5 7 8 3) but got %s", keys)
+	}
+	checkEval(t, `(def d (dict '(a 1) '(b 2) '(c 3))) (dict-delete d 'b) (dict-delete d 'a) (dict->list d)`, `((c 3))`)
+}
@@ -1,21 +1,56 @@
 package main
 
 import "fmt"
-import "bufio"
 import "os"
-import "strings"
 import "io"
 
 type Engine struct {
-	env *Env
+	env   *Env
+	core  *Env // primitives only, shared by the global env and modules
+	state *engineState
 }
 
 func NewEngine() Engine {
-	coreBindings := corePrimitives()
-	coreBindings["true"] = NewBoolean(true)
-	coreBindings["false"] = NewBoolean(false)
-	env := &Env{bindings: coreBindings, previous: nil}
-	return Engine{env}
+	e, err := newEngine(defaultPrelude)
+	if err != nil {
+		panic(fmt.Sprintf("default prelude - %s", err.Error()))
+	}
+	return e
+}
+
+func NewEngineWithPrelude(prelude string) (Engine, error) {
+	// an empty prelude opts out of the standard prelude
+	forms, err := parsePrelude(prelude)
+	if err != nil {
+		return Engine{}, err
+	}
+	return newEngine(func() ([]*topLevel, error) { return forms, nil })
+}
+
+func newEngine(prelude func() ([]*topLevel, error)) (Engine, error) {
+	state := newEngineState()
+	coreBindings := corePrimitives(state)
+	coreBindings[intern("true")] = NewBoolean(true)
+	coreBindings[intern("false")] = NewBoolean(false)
+	coreBindings[intern("stdin")] = state.stdin
+	coreBindings[intern("stdout")] = state.stdout
+	coreBindings[intern("stderr")] = state.stderr
+	core := &Env{bindings: coreBindings, previous: nil}
+	env := &Env{bindings: map[*vSymbol]Value{}, previous: core}
+	e := Engine{env, core, state}
+	for _, d := range modulePrimitives(e) {
+		update(core, intern(d.name), NewPrimitive(d.name, MakePrimitive(d)))
+	}
+	forms, err := prelude()
+	if err != nil {
+		return Engine{}, err
+	}
+	for _, top := range forms {
+		if _, _, err := e.runTop(core, top); err != nil {
+			return Engine{}, err
+		}
+	}
+	return e, nil
 }
 
 // TODO: engine.Read()
@@ -30,20 +65,132 @@ func NewEngine() Engine {
 
 // TODO: what do we export? Engine, Value
 
+type topLevelError struct {
+	kind string // READ, PARSE, EVAL, DECLARE
+	err  error
+}
+
+func (e *topLevelError) Error() string {
+	return fmt.Sprintf("%s ERROR - %s", e.kind, e.err.Error())
+}
+
+type topLevel struct {
+	def  *astDef
+	imp  *astImport
+	rec  *astRecord
+	expr ast
+}
+
+func parseTop(sexp Value) (*topLevel, error) {
+	d, err := parseDef(sexp)
+	if err != nil {
+		return nil, &topLevelError{"PARSE", err}
+	}
+	if d != nil {
+		return &topLevel{def: d}, nil
+	}
+	imp, err := parseImport(sexp)
+	if err != nil {
+		return nil, &topLevelError{"PARSE", err}
+	}
+	if imp != nil {
+		return &topLevel{imp: imp}, nil
+	}
+	rec, err := parseRecord(sexp)
+	if err != nil {
+		return nil, &topLevelError{"PARSE", err}
+	}
+	if rec != nil {
+		return &topLevel{rec: rec}, nil
+	}
+	m, err := parseModule(sexp)
+	if err != nil {
+		return nil, &topLevelError{"PARSE", err}
+	}
+	if m != nil {
+		return nil, &topLevelError{"DECLARE", fmt.Errorf("module %s not at the start of a module file", m.name)}
+	}
+	// check if it's an expression
+	expr, err := parseExpr(sexp)
+	if err != nil {
+		return nil, &topLevelError{"PARSE", err}
+	}
+	if expr == nil {
+		return nil, &topLevelError{"PARSE", fmt.Errorf("cannot parse %s", sexp.Display())}
+	}
+	return &topLevel{expr: expr}, nil
+}
+
+func (e Engine) runTop(env *Env, top *topLevel) (Value, string, error) {
+	// for a declaration, return the name declared
+	if d := top.def; d != nil {
+		if d.typ == DEF_FUNCTION {
+			update(env, d.sym, &vFunction{d.params, d.keys, d.body, env})
+			return nil, d.name, nil
+		}
+		if d.typ == DEF_VALUE {
+			v, err := d.body.eval(env)
+			if err != nil {
+				return nil, "", &topLevelError{"EVAL", err}
+			}
+			update(env, d.sym, firstValue(v))
+			return nil, d.name, nil
+		}
+		if d.typ == DEF_PATTERN {
+			v, err := d.body.eval(env)
+			if err != nil {
+				return nil, "", &topLevelError{"EVAL", err}
+			}
+			for i, v := range valuesToSlice(v) {
+				update(env, d.params[i], v)
+			}
+			return nil, d.name, nil
+		}
+		return nil, "", &topLevelError{"DECLARE", fmt.Errorf("unknow declaration type %d", d.typ)}
+	}
+	if imp := top.imp; imp != nil {
+		if err := e.importModule(env, imp); err != nil {
+			return nil, "", &topLevelError{"DECLARE", err}
+		}
+		return nil, imp.name, nil
+	}
+	if rec := top.rec; rec != nil {
+		defineRecord(env, rec)
+		return nil, rec.name, nil
+	}
+	v, err := top.expr.eval(env)
+	if err != nil {
+		return nil, "", &topLevelError{"EVAL", err}
+	}
+	return v, "", nil
+}
+
+func (e Engine) evalTop(env *Env, sexp Value) (Value, string, error) {
+	top, err := parseTop(sexp)
+	if err != nil {
+		return nil, "", err
+	}
+	return e.runTop(env, top)
+}
+
+func (e Engine) Load(path string) error {
+	return e.loadFile(e.env, e.state.resolve(path))
+}
+
 func (e Engine) Repl(prompt string) {
 	env := e.env
-	reader := bufio.NewReader(os.Stdin)
 	for {
 		fmt.Printf("%s> ", prompt)
-		text, err := reader.ReadString('\n')
+		text, err := e.state.stdin.readLine()
 		if err != nil {
 			if err == io.EOF {
 				fmt.Println()
 				bail()
 			}
-			fmt.Println("IO ERROR - ", err.Error())
+			fmt.Println("IO ERROR -", err.Error())
+			os.Exit(1)
 		}
-		if strings.TrimSpace(text) == "" {
+		if skipSpace(text) == "" {
 			continue
 		}
 		v, _, err := read(text)
@@ -51,49 +198,28 @@ func (e Engine) Repl(prompt string) {
 			fmt.Println("READ ERROR -", err.Error())
 			continue
 		}
-		// check if it's a declaration
-		d, err := parseDef(v)
-		if err != nil {
-			fmt.Println("PARSE ERROR -", err.Error())
-			continue
-		}
-		if d != nil {
-			if d.typ == DEF_FUNCTION {
-				update(env, d.name, &vFunction{d.params, d.body, env})
-				fmt.Println(d.name)
-				continue
-			}
-			if d.typ == DEF_VALUE {
-				v, err := d.body.eval(env)
-				if err != nil {
-					fmt.Println("EVAL ERROR -", err.Error())
-					continue
-				}
-				update(env, d.name, v)
-				fmt.Println(d.name)
-				continue
-			}
-			fmt.Println("DECLARE ERROR - unknow declaration type", d.typ)
-			continue
-		}
-		// check if it's an expression
-		e, err := parseExpr(v)
+		v, name, err := e.evalTop(env, v)
 		if err != nil {
-			fmt.Println("PARSE ERROR -", err.Error())
+			fmt.Println(err.Error())
 			continue
 		}
-		///fmt.Println("expr =", e.str())
-		v, err = e.eval(env)
-		if err != nil {
-			fmt.Println("EVAL ERROR -", err.Error())
+		if name != "" {
+			fmt.Println(name)
 			continue
 		}
-		if !v.isNil() {
-			fmt.Println(v.Display())
+		// each of multiple values on its own line
+		for _, v := range valuesToSlice(v) {
+			if !v.isNil() {
+				fmt.Println(prettyDisplay(v, terminalWidth()))
+			}
 		}
 	}
 }
 
+func (e Engine) PrettyPrint(v Value, width int) string {
+	return prettyDisplay(v, width)
+}
+
 func bail() {
 	os.Exit(0)
 }
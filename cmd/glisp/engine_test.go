@@ -0,0 +1,63 @@
+package main
+
+import "strings"
+import "testing"
+
+func evalSource(e Engine, src string) (Value, error) {
+	// the value of the last form in src
+	forms, err := readAll(src)
+	if err != nil {
+		return nil, err
+	}
+	var result Value = &vNil{}
+	for _, form := range forms {
+		v, _, err := e.evalTop(e.env, form)
+		if err != nil {
+			return nil, err
+		}
+		if v != nil {
+			result = v
+		}
+	}
+	return result, nil
+}
+
+func checkEval(t *testing.T, src string, expected string) {
+	t.Helper()
+	v, err := evalSource(NewEngine(), src)
+	if err != nil {
+		t.Errorf("%s - unexpected error %s", src, err.Error())
+		return
+	}
+	if v.Display() != expected {
+		t.Errorf("%s - expected %s but got %s", src, expected, v.Display())
+	}
+}
+
+func checkEvalError(t *testing.T, src string, expected string) {
+	t.Helper()
+	v, err := evalSource(NewEngine(), src)
+	if err == nil {
+		t.Errorf("%s - expected error %q but got %s", src, expected, v.Display())
+		return
+	}
+	if !strings.Contains(err.Error(), expected) {
+		t.Errorf("%s - expected error %q but got %q", src, expected, err.Error())
+	}
+}
+
+func TestExpansionHygiene(t *testing.T) {
+	checkEval(t, `(let ((call-with-env 5)) (with-env ((FOO "x")) (getenv "FOO")))`, `"x"`)
+}
+
+func TestWithOpenFileHygiene(t *testing.T) {
+	checkEval(t, `(let ((call-with-open-file 5)) (with-open-file (p "engine_test.go") (read-line p)))`, `"package main"`)
+}
+
+func TestDelayHygiene(t *testing.T) {
+	checkEval(t, `(let ((make-promise 5)) (force (delay (+ 1 2))))`, `3`)
+}
+
+func TestReceiveHygiene(t *testing.T) {
+	checkEval(t, `(let ((call-with-values 5)) (receive (a b) (values 1 2) (list a b)))`, `(1 2)`)
+}
@@ -2,12 +2,16 @@ package main
 
 import "fmt"
 
+// Bindings are keyed by symbol: symbols are interned, so looking a name
+// up compares pointers rather than strings, and a symbol made by gensym
+// never captures or is captured by a name written in the source.
+
 type Env struct {
-	bindings map[string]Value
+	bindings map[*vSymbol]Value
 	previous *Env
 }
 
-func find(env *Env, name string) (Value, error) {
+func find(env *Env, name *vSymbol) (Value, error) {
 	current := env
 	for current != nil {
 		val, ok := current.bindings[name]
@@ -16,17 +20,17 @@ func find(env *Env, name string) (Value, error) {
 		}
 		current = current.previous
 	}
-	return nil, fmt.Errorf("no such identifier %s", name)
+	return nil, fmt.Errorf("no such identifier %s", name.name)
 }
 
-func update(env *Env, name string, v Value) {
+func update(env *Env, name *vSymbol, v Value) {
 	env.bindings[name] = v
 }
 
-func layer(env *Env, names []string, values []Value) *Env {
+func layer(env *Env, names []*vSymbol, values []Value) *Env {
 	// if values is nil or smaller than names, then
 	// remaining names are bound to #nil
-	bindings := map[string]Value{}
+	bindings := map[*vSymbol]Value{}
 	for i, name := range names {
 		if values != nil && i < len(values) {
 			bindings[name] = values[i]
@@ -0,0 +1,25 @@
+package main
+
+import "testing"
+
+func TestEnvKeyedBySymbol(t *testing.T) {
+	g := gensym("x")
+	x := intern(g.name)
+	env := layer(nil, []*vSymbol{x}, []Value{NewInteger(1)})
+	env = layer(env, []*vSymbol{g}, []Value{NewInteger(2)})
+	if v, err := find(env, x); err != nil || v.Display() != "1" {
+		t.Errorf("find %s - got %v, %v", x.name, v, err)
+	}
+	if v, err := find(env, g); err != nil || v.Display() != "2" {
+		t.Errorf("find gensym %s - got %v, %v", g.name, v, err)
+	}
+	if _, err := find(env, gensym("x")); err == nil {
+		t.Errorf("find fresh gensym - expected an error")
+	}
+}
+
+func TestExpansionNamesNotCaptured(t *testing.T) {
+	// the names introduced by expansions are uninterned
+	checkEval(t, `(let ((__temp1 1) (__temp2 2) (__pattern3 3)) (do 0 (list __temp1 __temp2 __pattern3)))`, `(1 2 3)`)
+	checkEval(t, `(let (([a b] [1 2]) (__pattern1 3)) (list a b __pattern1))`, `(1 2 3)`)
+}
@@ -0,0 +1,244 @@
+package main
+
+import "hash"
+import "hash/fnv"
+import "sort"
+
+// Two notions of equality: identity, where containers (conses, arrays,
+// dicts, references, vectors, hash-maps, sets, records) are only equal to
+// themselves, and structural equality, which compares contents. Structural
+// equality terminates on cyclic data by assuming a pair of containers
+// already being compared is equal.
+
+const HASH_NODES = 64
+
+type equalPair struct {
+	v1 Value
+	v2 Value
+}
+
+func isContainer(v Value) bool {
+	switch v.(type) {
+	case *vCons, *vArray, *vDict, *vReference, *vVector, *vMap, *vSet, *vRecord:
+		return true
+	}
+	return false
+}
+
+func identical(v1 Value, v2 Value) bool {
+	if isContainer(v1) || isContainer(v2) {
+		return v1 == v2
+	}
+	return v1.isEqual(v2)
+}
+
+func equalValues(v1 Value, v2 Value) bool {
+	return equalIn(v1, v2, map[equalPair]bool{})
+}
+
+func equalIn(v1 Value, v2 Value, seen map[equalPair]bool) bool {
+	if v1 == v2 {
+		return true
+	}
+	if !isContainer(v1) || !isContainer(v2) {
+		if isContainer(v1) || isContainer(v2) {
+			return false
+		}
+		return v1.isEqual(v2)
+	}
+	pair := equalPair{v1, v2}
+	if seen[pair] {
+		return true
+	}
+	seen[pair] = true
+	if _, _, ok := v1.asCons(); ok {
+		// lists themselves cannot be cyclic, so walk the spine directly
+		curr1, curr2 := v1, v2
+		for {
+			head1, tail1, ok1 := curr1.asCons()
+			head2, tail2, ok2 := curr2.asCons()
+			if !ok1 || !ok2 {
+				return equalIn(curr1, curr2, seen)
+			}
+			if !equalIn(head1, head2, seen) {
+				return false
+			}
+			curr1, curr2 = tail1, tail2
+		}
+	}
+	if a1, ok := v1.(*vArray); ok {
+		a2, ok := v2.(*vArray)
+		if !ok || len(a1.content) != len(a2.content) {
+			return false
+		}
+		for i := range a1.content {
+			if !equalIn(a1.content[i], a2.content[i], seen) {
+				return false
+			}
+		}
+		return true
+	}
+	if d1, ok := v1.(*vDict); ok {
+		// insertion order does not matter
+		d2, ok := v2.(*vDict)
+		if !ok || d1.content.size() != d2.content.size() {
+			return false
+		}
+		for _, entry := range d1.content.entries() {
+			other, ok, _ := d2.content.get(entry.key)
+			if !ok || !equalIn(entry.value, other, seen) {
+				return false
+			}
+		}
+		return true
+	}
+	if r1, ok := v1.(*vReference); ok {
+		r2, ok := v2.(*vReference)
+		return ok && equalIn(r1.content, r2.content, seen)
+	}
+	if p1, ok := v1.(*vVector); ok {
+		p2, ok := v2.(*vVector)
+		if !ok || p1.content.count != p2.content.count {
+			return false
+		}
+		for i := 0; i < p1.content.count; i++ {
+			if !equalIn(p1.content.nth(i), p2.content.nth(i), seen) {
+				return false
+			}
+		}
+		return true
+	}
+	if m1, ok := v1.(*vMap); ok {
+		m2, ok := v2.(*vMap)
+		if !ok || m1.content.count != m2.content.count {
+			return false
+		}
+		for _, e := range m1.content.entries() {
+			other, ok, _ := m2.content.get(e.key)
+			if !ok || !equalIn(e.value, other, seen) {
+				return false
+			}
+		}
+		return true
+	}
+	if r1, ok := v1.(*vRecord); ok {
+		r2, ok := v2.(*vRecord)
+		if !ok || r1.rtype != r2.rtype {
+			return false
+		}
+		for i := range r1.fields {
+			if !equalIn(r1.fields[i], r2.fields[i], seen) {
+				return false
+			}
+		}
+		return true
+	}
+	if s1, ok := v1.(*vSet); ok {
+		// elements are hashable, so membership decides
+		s2, ok := v2.(*vSet)
+		return ok && isSubset(s1.content, s2.content) && s1.content.count == s2.content.count
+	}
+	return false
+}
+
+func hashValue(v Value) int {
+	h := fnv.New64a()
+	budget := HASH_NODES
+	writeHash(h, v, &budget)
+	return int(h.Sum64() >> 1)
+}
+
+func writeHash(h hash.Hash64, v Value, budget *int) {
+	// only the first few values met in a fixed order contribute, which
+	// keeps the hash of cyclic or shared values cheap to compute and
+	// consistent with equalValues
+	if *budget == 0 {
+		return
+	}
+	*budget -= 1
+	h.Write([]byte(v.typ()))
+	if _, _, ok := v.asCons(); ok {
+		h.Write([]byte("("))
+		current := v
+		for head, next, ok := v.asCons(); ok; head, next, ok = next.asCons() {
+			writeHash(h, head, budget)
+			current = next
+		}
+		writeHash(h, current, budget)
+		return
+	}
+	if a, ok := v.(*vArray); ok {
+		h.Write([]byte("["))
+		for _, item := range a.content {
+			writeHash(h, item, budget)
+		}
+		return
+	}
+	if p, ok := v.(*vVector); ok {
+		h.Write([]byte("["))
+		for i := 0; i < p.content.count; i++ {
+			writeHash(h, p.content.nth(i), budget)
+		}
+		return
+	}
+	if r, ok := v.(*vRecord); ok {
+		for _, field := range r.fields {
+			writeHash(h, field, budget)
+		}
+		return
+	}
+	if d, ok := v.(*vDict); ok {
+		writeEntriesHash(h, d.content.keys(), d.content.values(), budget)
+		return
+	}
+	if m, ok := v.(*vMap); ok {
+		entries := m.content.entries()
+		keys := make([]Value, len(entries))
+		values := make([]Value, len(entries))
+		for i, e := range entries {
+			keys[i] = e.key
+			values[i] = e.value
+		}
+		writeEntriesHash(h, keys, values, budget)
+		return
+	}
+	if s, ok := v.(*vSet); ok {
+		var sum uint64
+		for _, e := range s.content.entries() {
+			eh := fnv.New64a()
+			eh.Write([]byte(e.id))
+			sum += eh.Sum64()
+		}
+		var buf [8]byte
+		for i := range buf {
+			buf[i] = byte(sum >> (8 * i))
+		}
+		h.Write(buf[:])
+		return
+	}
+	if r, ok := v.(*vReference); ok {
+		writeHash(h, r.content, budget)
+		return
+	}
+	if key, ok := hashKey(v); ok {
+		h.Write([]byte(key))
+		return
+	}
+	h.Write([]byte(v.Display()))
+}
+
+func writeEntriesHash(h hash.Hash64, keys []Value, values []Value, budget *int) {
+	// visit entries in the order of their keys so that insertion order
+	// does not matter
+	ids := make([]string, len(keys))
+	order := make([]int, len(keys))
+	for i, key := range keys {
+		ids[i], _ = hashKey(key)
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool { return ids[order[i]] < ids[order[j]] })
+	for _, i := range order {
+		h.Write([]byte(ids[i]))
+		writeHash(h, values[i], budget)
+	}
+}
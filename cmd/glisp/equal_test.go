@@ -0,0 +1,18 @@
+package main
+
+import "testing"
+
+func TestStructuralEquality(t *testing.T) {
+	checkEval(t, `(list (= (array 1 2) (array 1 2)) (equal? (array 1 2) (array 1 2)) (eq? (array 1 2) (array 1 2)))`, `(#t #t #f)`)
+	checkEval(t, `(def a (array 1 2)) (eq? a a)`, `#t`)
+	checkEval(t, `(equal? (dict '(a 1) '(b 2)) (dict '(b 2) '(a 1)))`, `#t`)
+	checkEval(t, `(= (hash (dict '(a 1) '(b 2))) (hash (dict '(b 2) '(a 1))))`, `#t`)
+	checkEval(t, `(= (hash (list 1 (array 2 3))) (hash (list 1 (array 2 3))))`, `#t`)
+}
+
+func TestHashSharedAndCyclicValues(t *testing.T) {
+	// an array holding itself 30 times would take exponential time to
+	// hash if every path through it counted
+	checkEval(t, `(def a (make-array 30 0)) (array-fill! a a) (list (equal? a a) (number? (hash a)))`, `(#t #t)`)
+	checkEval(t, `(def a (make-array 30 0)) (def b (make-array 30 0)) (array-fill! a a) (array-fill! b b) (list (equal? a b) (= (hash a) (hash b)))`, `(#t #t)`)
+}
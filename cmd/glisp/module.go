@@ -0,0 +1,260 @@
+package main
+
+import "errors"
+import "fmt"
+import "io/ioutil"
+import "os"
+import "path"
+import "path/filepath"
+
+// A module is a file whose first form is (module name (export name ...)).
+// It is evaluated once in its own environment on top of the primitives,
+// and (import name) binds its exports as name/x, or (import name (prefix p))
+// and (import name :prefix p) as px. Module files are found in the current
+// directory and then in the directories listed in GLISP_PATH.
+
+const kw_MODULE string = "module"
+const kw_EXPORT string = "export"
+const kw_IMPORT string = "import"
+const kw_PREFIX string = "prefix"
+
+const MODULE_EXT = ".glisp"
+
+type astModule struct {
+	name    string
+	exports []string
+}
+
+type astImport struct {
+	name   string
+	prefix string
+}
+
+type module struct {
+	name    string
+	exports map[string]Value
+	loading bool
+}
+
+func parseModule(sexp Value) (*astModule, error) {
+	head, next, ok := sexp.asCons()
+	if !ok {
+		return nil, nil
+	}
+	if !parseKeyword(kw_MODULE, head) {
+		return nil, nil
+	}
+	head1, next, ok := next.asCons()
+	if !ok {
+		return nil, errors.New("too few arguments to module")
+	}
+	name, ok := head1.asSymbol()
+	if !ok {
+		return nil, errors.New("module name not a symbol")
+	}
+	exports := []string{}
+	if head2, next2, ok := next.asCons(); ok {
+		head3, rest, ok := head2.asCons()
+		if !ok || !parseKeyword(kw_EXPORT, head3) {
+			return nil, errors.New("expected (export name ...) in module")
+		}
+		names, err := parseNames(rest)
+		if err != nil {
+			return nil, err
+		}
+		exports = names
+		next = next2
+	}
+	if !next.isEmpty() {
+		return nil, errors.New("too many arguments to module")
+	}
+	return &astModule{name, exports}, nil
+}
+
+func parseImport(sexp Value) (*astImport, error) {
+	head, next, ok := sexp.asCons()
+	if !ok {
+		return nil, nil
+	}
+	if !parseKeyword(kw_IMPORT, head) {
+		return nil, nil
+	}
+	head1, next, ok := next.asCons()
+	if !ok {
+		return nil, errors.New("too few arguments to import")
+	}
+	name, ok := head1.asSymbol()
+	if !ok {
+		return nil, errors.New("module name not a symbol")
+	}
+	// qualified by default, using the last component of the name
+	prefix := path.Base(name) + "/"
+	if head2, next2, ok := next.asCons(); ok && isKeyword(head2, kw_PREFIX) {
+		head3, next3, ok := next2.asCons()
+		if !ok {
+			return nil, errors.New("expected :prefix name in import")
+		}
+		p, ok := head3.asSymbol()
+		if !ok {
+			return nil, errors.New("import prefix not a symbol")
+		}
+		prefix = p
+		next = next3
+	} else if ok {
+		head3, rest, ok := head2.asCons()
+		if !ok || !parseKeyword(kw_PREFIX, head3) {
+			return nil, errors.New("expected (prefix name) in import")
+		}
+		head4, rest, ok := rest.asCons()
+		if !ok || !rest.isEmpty() {
+			return nil, errors.New("expected (prefix name) in import")
+		}
+		p, ok := head4.asSymbol()
+		if !ok {
+			return nil, errors.New("import prefix not a symbol")
+		}
+		prefix = p
+		next = next2
+	}
+	if !next.isEmpty() {
+		return nil, errors.New("too many arguments to import")
+	}
+	return &astImport{name, prefix}, nil
+}
+
+func isKeyword(v Value, name string) bool {
+	k, ok := v.asKeyword()
+	return ok && k == name
+}
+
+func readAll(text string) ([]Value, error) {
+	result := []Value{}
+	rest := text
+	for skipSpace(rest) != "" {
+		v, r, err := read(rest)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, v)
+		rest = r
+	}
+	return result, nil
+}
+
+func readFileForms(file string) ([]Value, error) {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	forms, err := readAll(string(content))
+	if err != nil {
+		return nil, &topLevelError{"READ", err}
+	}
+	return forms, nil
+}
+
+func (e Engine) loadFile(env *Env, file string) error {
+	forms, err := readFileForms(file)
+	if err != nil {
+		return fmt.Errorf("%s: %s", file, err.Error())
+	}
+	for _, form := range forms {
+		if _, _, err := e.evalTop(env, form); err != nil {
+			return fmt.Errorf("%s: %s", file, err.Error())
+		}
+	}
+	return nil
+}
+
+func (e Engine) findModule(name string) (string, error) {
+	dirs := []string{e.state.dir}
+	if p, ok := e.state.environ["GLISP_PATH"]; ok {
+		dirs = append(dirs, filepath.SplitList(p)...)
+	}
+	for _, dir := range dirs {
+		file := filepath.Join(e.state.resolve(dir), filepath.FromSlash(name)+MODULE_EXT)
+		if info, err := os.Stat(file); err == nil && !info.IsDir() {
+			return file, nil
+		}
+	}
+	return "", fmt.Errorf("cannot find module %s", name)
+}
+
+func (e Engine) loadModule(name string) (*module, error) {
+	if m, ok := e.state.modules[name]; ok {
+		if m.loading {
+			return nil, fmt.Errorf("circular import of module %s", name)
+		}
+		return m, nil
+	}
+	file, err := e.findModule(name)
+	if err != nil {
+		return nil, err
+	}
+	forms, err := readFileForms(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", file, err.Error())
+	}
+	if len(forms) == 0 {
+		return nil, fmt.Errorf("%s: missing module declaration", file)
+	}
+	decl, err := parseModule(forms[0])
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", file, err.Error())
+	}
+	if decl == nil {
+		return nil, fmt.Errorf("%s: missing module declaration", file)
+	}
+	if decl.name != name {
+		return nil, fmt.Errorf("%s: declares module %s instead of %s", file, decl.name, name)
+	}
+	m := &module{name: name, exports: map[string]Value{}, loading: true}
+	e.state.modules[name] = m
+	env := layer(e.core, []*vSymbol{}, nil)
+	for _, form := range forms[1:] {
+		if _, _, err := e.evalTop(env, form); err != nil {
+			delete(e.state.modules, name)
+			return nil, fmt.Errorf("%s: %s", file, err.Error())
+		}
+	}
+	for _, export := range decl.exports {
+		v, ok := env.bindings[intern(export)]
+		if !ok {
+			delete(e.state.modules, name)
+			return nil, fmt.Errorf("%s: exported name %s not defined", file, export)
+		}
+		m.exports[export] = v
+	}
+	m.loading = false
+	return m, nil
+}
+
+func (e Engine) importModule(env *Env, imp *astImport) error {
+	m, err := e.loadModule(imp.name)
+	if err != nil {
+		return err
+	}
+	for name, v := range m.exports {
+		update(env, intern(imp.prefix+name), v)
+	}
+	return nil
+}
+
+func modulePrimitives(e Engine) []Primitive {
+	return []Primitive{
+
+		Primitive{"load", 1, 1,
+			func(name string, args []Value) (Value, error) {
+				// plain inclusion into the global environment
+				file, err := e.state.pathArg(name, args, 0)
+				if err != nil {
+					return nil, err
+				}
+				if err := e.loadFile(e.env, file); err != nil {
+					return nil, fmt.Errorf("%s - %s", name, err.Error())
+				}
+				return NewNil(), nil
+			},
+		},
+	}
+}
@@ -0,0 +1,34 @@
+package main
+
+import "fmt"
+import "io/ioutil"
+import "path/filepath"
+import "testing"
+
+func writeModules(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, text := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(text), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestModules(t *testing.T) {
+	dir := writeModules(t, map[string]string{
+		"util.glisp":   "(module util (export double))\n(def (helper x) (+ x x))\n(def (double x) (helper x))\n",
+		"broken.glisp": "(module broken (export missing))\n",
+		"a.glisp":      "(module a (export x))\n(import b)\n(def x 1)\n",
+		"b.glisp":      "(module b (export y))\n(import a)\n(def y 2)\n",
+		"script.glisp": "(def loaded 42)\n",
+	})
+	checkEval(t, fmt.Sprintf(`(cd %q) (import util) (util/double 21)`, dir), `42`)
+	checkEval(t, fmt.Sprintf(`(setenv "GLISP_PATH" %q) (import util (prefix u-)) (u-double 2)`, dir), `4`)
+	checkEvalError(t, fmt.Sprintf(`(cd %q) (import util) (util/helper 2)`, dir), "no such identifier util/helper")
+	checkEvalError(t, fmt.Sprintf(`(cd %q) (import broken)`, dir), "exported name missing not defined")
+	checkEvalError(t, fmt.Sprintf(`(cd %q) (import a)`, dir), "circular import of module a")
+	checkEvalError(t, `(import no-such-module)`, "cannot find module no-such-module")
+	checkEval(t, fmt.Sprintf(`(cd %q) (load "script.glisp") loaded`, dir), `42`)
+}
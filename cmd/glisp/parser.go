@@ -12,19 +12,21 @@ const kw_IF string = "if"
 const kw_FUN string = "fn"
 const kw_QUOTE string = "quote"
 const kw_DO string = "do"
+const kw_WITHENV string = "with-env"
+const kw_WITHOPENFILE string = "with-open-file"
+const kw_DELAY string = "delay"
+const kw_LETVALUES string = "let-values"
+const kw_RECEIVE string = "receive"
+const kw_KEY string = "&key"
 
 const kw_MACRO string = "macro"
 const kw_AND string = "and"
 const kw_OR string = "or"
 
-var fresh = (func(init int) func(string) string {
-	id := init
-	return func(prefix string) string {
-		result := fmt.Sprintf("%s_%d", prefix, id)
-		id += 1
-		return result
-	}
-})(0)
+func fresh(prefix string) *vSymbol {
+	// uninterned, so that no name in the source can refer to it
+	return gensym(prefix)
+}
 
 func parseDef(sexp Value) (*astDef, error) {
 	head, next, ok := sexp.asCons()
@@ -39,7 +41,7 @@ func parseDef(sexp Value) (*astDef, error) {
 	if !ok {
 		return nil, errors.New("too few arguments to def")
 	}
-	if name, ok := defBlock.asSymbol(); ok {
+	if name, ok := defBlock.(*vSymbol); ok {
 		// next = next.tailValue()
 		head, next, ok := next.asCons()
 		if !ok {
@@ -52,14 +54,36 @@ func parseDef(sexp Value) (*astDef, error) {
 		if !next.isEmpty() {
 			return nil, errors.New("too many arguments to def")
 		}
-		return &astDef{name, DEF_VALUE, nil, value}, nil
+		return &astDef{name.name, name, DEF_VALUE, nil, nil, value}, nil
+	}
+	if source, ok := defPattern(defBlock); ok {
+		pattern, err := literalValue(source)
+		if err != nil {
+			return nil, err
+		}
+		names, err := patternNames(pattern)
+		if err != nil {
+			return nil, err
+		}
+		head, next, ok := next.asCons()
+		if !ok {
+			return nil, errors.New("too few arguments to def")
+		}
+		value, err := parseExpr(head)
+		if err != nil {
+			return nil, err
+		}
+		if !next.isEmpty() {
+			return nil, errors.New("too many arguments to def")
+		}
+		return &astDef{pattern.Display(), nil, DEF_PATTERN, names, nil, makeDestructure(pattern, value)}, nil
 	}
 	if head, tail, ok := defBlock.asCons(); ok {
-		name, ok := head.asSymbol()
+		name, ok := head.(*vSymbol)
 		if !ok {
-			return nil, errors.New("definition name not a symbol")
+			return nil, errors.New("definition name not a symbol - use (def '(a b) expr) to destructure a list")
 		}
-		params, err := parseSymbols(tail)
+		params, keys, patterns, err := parseParams(tail)
 		if err != nil {
 			return nil, err
 		}
@@ -75,17 +99,38 @@ func parseDef(sexp Value) (*astDef, error) {
 		if !next.isEmpty() {
 			return nil, errors.New("too many arguments to def")
 		}
-		return &astDef{name, DEF_FUNCTION, params, body}, nil
+		return &astDef{name.name, name, DEF_FUNCTION, params, keys, destructurePatterns(params, patterns, body)}, nil
 	}
 	return nil, errors.New("malformed def")
 }
 
+func defPattern(sexp Value) (Value, bool) {
+	// array and dict patterns as they are, and list patterns quoted since
+	// (def (f x) body) defines a function: (def '(a b . rest) expr)
+	if _, ok := sexp.(*vVector); ok {
+		return sexp, true
+	}
+	if _, _, ok := collectionForm(sexp); ok {
+		return sexp, true
+	}
+	if head, next, ok := sexp.asCons(); ok && parseKeyword(kw_QUOTE, head) {
+		if pattern, rest, ok := next.asCons(); ok && rest.isEmpty() {
+			return pattern, true
+		}
+	}
+	return nil, false
+}
+
 func parseExpr(sexp Value) (ast, error) {
 	expr := parseAtom(sexp)
 	if expr != nil {
 		return expr, nil
 	}
-	expr, err := parseastQuote(sexp)
+	expr, err := parseCollection(sexp)
+	if err != nil || expr != nil {
+		return expr, err
+	}
+	expr, err = parseastQuote(sexp)
 	if err != nil || expr != nil {
 		return expr, err
 	}
@@ -113,6 +158,26 @@ func parseExpr(sexp Value) (ast, error) {
 	if err != nil || expr != nil {
 		return expr, err
 	}
+	expr, err = parseWithEnv(sexp)
+	if err != nil || expr != nil {
+		return expr, err
+	}
+	expr, err = parseWithOpenFile(sexp)
+	if err != nil || expr != nil {
+		return expr, err
+	}
+	expr, err = parseDelay(sexp)
+	if err != nil || expr != nil {
+		return expr, err
+	}
+	expr, err = parseLetValues(sexp)
+	if err != nil || expr != nil {
+		return expr, err
+	}
+	expr, err = parseReceive(sexp)
+	if err != nil || expr != nil {
+		return expr, err
+	}
 	expr, err = parseastApply(sexp)
 	if err != nil || expr != nil {
 		return expr, err
@@ -121,7 +186,7 @@ func parseExpr(sexp Value) (ast, error) {
 }
 
 func parseAtom(sexp Value) ast {
-	if name, ok := sexp.asSymbol(); ok {
+	if name, ok := sexp.(*vSymbol); ok {
 		return &astId{name}
 	}
 	if sexp.isAtom() {
@@ -130,6 +195,34 @@ func parseAtom(sexp Value) ast {
 	return nil
 }
 
+func parseCollection(sexp Value) (ast, error) {
+	// [a b], {k v} and #{a b} evaluate their items, as (vector a b),
+	// (hash-map k v) and (set a b)
+	var items []Value
+	var constructor *vSymbol
+	if v, ok := sexp.(*vVector); ok {
+		items = v.content.toSlice()
+		constructor = intern("vector")
+	} else if tag, forms, ok := collectionForm(sexp); ok {
+		items = forms
+		constructor = intern(tag.name)
+	} else {
+		return nil, nil
+	}
+	args := make([]ast, len(items))
+	for i, item := range items {
+		arg, err := parseExpr(item)
+		if err != nil {
+			return nil, err
+		}
+		if arg == nil {
+			return nil, fmt.Errorf("cannot parse %s", item.Display())
+		}
+		args[i] = arg
+	}
+	return &astApply{&astPrimitive{constructor}, args}, nil
+}
+
 func parseKeyword(kw string, sexp Value) bool {
 	name, ok := sexp.asSymbol()
 	if !ok {
@@ -155,7 +248,11 @@ func parseastQuote(sexp Value) (ast, error) {
 	if !next.isEmpty() {
 		return nil, errors.New("too many arguments to quote")
 	}
-	return &astQuote{head1}, nil
+	v, err := literalValue(head1)
+	if err != nil {
+		return nil, err
+	}
+	return &astQuote{v}, nil
 }
 
 func parseastIf(sexp Value) (ast, error) {
@@ -219,7 +316,7 @@ func parseFunction(sexp Value) (ast, error) {
 		// restart from scratch
 		return parseRecFunction(sexp)
 	}
-	params, err := parseSymbols(head1)
+	params, keys, patterns, err := parseParams(head1)
 	if err != nil {
 		return nil, err
 	}
@@ -235,7 +332,7 @@ func parseFunction(sexp Value) (ast, error) {
 	if !next.isEmpty() {
 		return nil, errors.New("too many arguments to fun")
 	}
-	return makeFunction(params, body), nil
+	return makeRecFunction(fresh("__temp"), params, keys, destructurePatterns(params, patterns, body)), nil
 }
 
 func parseRecFunction(sexp Value) (ast, error) {
@@ -252,14 +349,14 @@ func parseRecFunction(sexp Value) (ast, error) {
 	if !ok {
 		return nil, errors.New("too few arguments to fun")
 	}
-	recName, _ := head1.asSymbol()
+	recName, _ := head1.(*vSymbol)
 	// TODO: check type of recName? guess it was already done in parseFunction...
 	// next = next.tailValue()
 	head2, next, ok := next.asCons()
 	if !ok {
 		return nil, errors.New("too few arguments to fun")
 	}
-	params, err := parseSymbols(head2)
+	params, keys, patterns, err := parseParams(head2)
 	if err != nil {
 		return nil, err
 	}
@@ -275,7 +372,7 @@ func parseRecFunction(sexp Value) (ast, error) {
 	if !next.isEmpty() {
 		return nil, errors.New("too many arguments to fun")
 	}
-	return makeRecFunction(recName, params, body), nil
+	return makeRecFunction(recName, params, keys, destructurePatterns(params, patterns, body)), nil
 }
 
 func parseLet(sexp Value) (ast, error) {
@@ -292,7 +389,7 @@ func parseLet(sexp Value) (ast, error) {
 	if !ok {
 		return nil, errors.New("too few arguments to let")
 	}
-	params, bindings, err := parseBindings(head1)
+	params, patterns, bindings, err := parseBindings(head1)
 	if err != nil {
 		return nil, err
 	}
@@ -308,7 +405,7 @@ func parseLet(sexp Value) (ast, error) {
 	if !next.isEmpty() {
 		return nil, errors.New("too many arguments to let")
 	}
-	return makeLet(params, bindings, body), nil
+	return makeLet(params, bindings, destructurePatterns(params, patterns, body)), nil
 }
 
 func parseLetStar(sexp Value) (ast, error) {
@@ -325,7 +422,7 @@ func parseLetStar(sexp Value) (ast, error) {
 	if !ok {
 		return nil, errors.New("too few arguments to let*")
 	}
-	params, bindings, err := parseBindings(head1)
+	params, patterns, bindings, err := parseBindings(head1)
 	if err != nil {
 		return nil, err
 	}
@@ -341,7 +438,7 @@ func parseLetStar(sexp Value) (ast, error) {
 	if !next.isEmpty() {
 		return nil, errors.New("too many arguments to let*")
 	}
-	return makeLetStar(params, bindings, body), nil
+	return makeLetStar(params, patterns, bindings, body), nil
 }
 
 func parseastLetRec(sexp Value) (ast, error) {
@@ -358,7 +455,7 @@ func parseastLetRec(sexp Value) (ast, error) {
 	if !ok {
 		return nil, errors.New("too few arguments to letrec")
 	}
-	names, params, bodies, err := parseFunBindings(head1)
+	names, params, keys, bodies, err := parseFunBindings(head1)
 	if err != nil {
 		return nil, err
 	}
@@ -374,106 +471,350 @@ func parseastLetRec(sexp Value) (ast, error) {
 	if !next.isEmpty() {
 		return nil, errors.New("too many arguments to letrec")
 	}
-	return &astLetRec{names, params, bodies, body}, nil
+	return &astLetRec{names, params, keys, bodies, body}, nil
+}
+
+func parseWithEnv(sexp Value) (ast, error) {
+	head, next, ok := sexp.asCons()
+	if !ok {
+		return nil, nil
+	}
+	isWithEnv := parseKeyword(kw_WITHENV, head)
+	if !isWithEnv {
+		return nil, nil
+	}
+	head1, next, ok := next.asCons()
+	if !ok {
+		return nil, errors.New("too few arguments to with-env")
+	}
+	names, patterns, values, err := parseBindings(head1)
+	if err != nil {
+		return nil, err
+	}
+	for _, pattern := range patterns {
+		if pattern != nil {
+			return nil, errors.New("expected name in with-env binding")
+		}
+	}
+	head2, next, ok := next.asCons()
+	if !ok {
+		return nil, errors.New("too few arguments to with-env")
+	}
+	body, err := parseExpr(head2)
+	if err != nil {
+		return nil, err
+	}
+	if !next.isEmpty() {
+		return nil, errors.New("too many arguments to with-env")
+	}
+	return makeWithEnv(names, values, body), nil
+}
+
+func parseWithOpenFile(sexp Value) (ast, error) {
+	head, next, ok := sexp.asCons()
+	if !ok {
+		return nil, nil
+	}
+	isWithOpenFile := parseKeyword(kw_WITHOPENFILE, head)
+	if !isWithOpenFile {
+		return nil, nil
+	}
+	head1, next, ok := next.asCons()
+	if !ok {
+		return nil, errors.New("too few arguments to with-open-file")
+	}
+	// (port path [mode])
+	portSexp, spec, ok := head1.asCons()
+	if !ok {
+		return nil, errors.New("expected (name path [mode]) in with-open-file")
+	}
+	port, ok := portSexp.(*vSymbol)
+	if !ok {
+		return nil, errors.New("expected name in with-open-file")
+	}
+	exprs, err := parseExprs(spec)
+	if err != nil {
+		return nil, err
+	}
+	if len(exprs) < 1 || len(exprs) > 2 {
+		return nil, errors.New("expected (name path [mode]) in with-open-file")
+	}
+	if len(exprs) == 1 {
+		exprs = append(exprs, &astQuote{NewSymbol("read")})
+	}
+	head2, next, ok := next.asCons()
+	if !ok {
+		return nil, errors.New("too few arguments to with-open-file")
+	}
+	body, err := parseExpr(head2)
+	if err != nil {
+		return nil, err
+	}
+	if !next.isEmpty() {
+		return nil, errors.New("too many arguments to with-open-file")
+	}
+	args := append(exprs, makeFunction([]*vSymbol{port}, body))
+	return &astApply{&astPrimitive{intern("call-with-open-file")}, args}, nil
+}
+
+func parseDelay(sexp Value) (ast, error) {
+	head, next, ok := sexp.asCons()
+	if !ok {
+		return nil, nil
+	}
+	isDelay := parseKeyword(kw_DELAY, head)
+	if !isDelay {
+		return nil, nil
+	}
+	head1, next, ok := next.asCons()
+	if !ok {
+		return nil, errors.New("too few arguments to delay")
+	}
+	body, err := parseExpr(head1)
+	if err != nil {
+		return nil, err
+	}
+	if !next.isEmpty() {
+		return nil, errors.New("too many arguments to delay")
+	}
+	return &astApply{&astPrimitive{intern("make-promise")}, []ast{makeFunction([]*vSymbol{}, body)}}, nil
+}
+
+func makeCallWithValues(params []*vSymbol, expr ast, body ast) ast {
+	producer := makeFunction([]*vSymbol{}, expr)
+	return &astApply{&astPrimitive{intern("call-with-values")}, []ast{producer, makeFunction(params, body)}}
+}
+
+func parseLetValues(sexp Value) (ast, error) {
+	// (let-values (((a b) e1) ((c) e2)) body) receives the values of each
+	// expression into temporaries, so that like let no expression sees
+	// the names bound by the others
+	head, next, ok := sexp.asCons()
+	if !ok {
+		return nil, nil
+	}
+	isLetValues := parseKeyword(kw_LETVALUES, head)
+	if !isLetValues {
+		return nil, nil
+	}
+	head1, next, ok := next.asCons()
+	if !ok {
+		return nil, errors.New("too few arguments to let-values")
+	}
+	head2, next, ok := next.asCons()
+	if !ok {
+		return nil, errors.New("too few arguments to let-values")
+	}
+	if !next.isEmpty() {
+		return nil, errors.New("too many arguments to let-values")
+	}
+	body, err := parseExpr(head2)
+	if err != nil {
+		return nil, err
+	}
+	formals := [][]*vSymbol{}
+	exprs := []ast{}
+	current := head1
+	for binding, rest, ok := head1.asCons(); ok; binding, rest, ok = rest.asCons() {
+		params, expr, err := parseValuesBinding(binding)
+		if err != nil {
+			return nil, err
+		}
+		formals = append(formals, params)
+		exprs = append(exprs, expr)
+		current = rest
+	}
+	if !current.isEmpty() {
+		return nil, errors.New("malformed let-values bindings")
+	}
+	allParams := []*vSymbol{}
+	temps := []ast{}
+	tempParams := make([][]*vSymbol, len(formals))
+	for i, params := range formals {
+		tempParams[i] = make([]*vSymbol, len(params))
+		for j, param := range params {
+			tempParams[i][j] = fresh("__temp")
+			allParams = append(allParams, param)
+			temps = append(temps, &astId{tempParams[i][j]})
+		}
+	}
+	result := makeLet(allParams, temps, body)
+	for i := len(exprs) - 1; i >= 0; i-- {
+		result = makeCallWithValues(tempParams[i], exprs[i], result)
+	}
+	return result, nil
 }
 
-func parseBindings(sexp Value) ([]string, []ast, error) {
-	params := make([]string, 0)
+func parseValuesBinding(sexp Value) ([]*vSymbol, ast, error) {
+	head, next, ok := sexp.asCons()
+	if !ok {
+		return nil, nil, errors.New("expected ((name ...) expr) in let-values")
+	}
+	params, err := parseSymbols(head)
+	if err != nil {
+		return nil, nil, err
+	}
+	head, next, ok = next.asCons()
+	if !ok || !next.isEmpty() {
+		return nil, nil, errors.New("expected ((name ...) expr) in let-values")
+	}
+	expr, err := parseExpr(head)
+	if err != nil {
+		return nil, nil, err
+	}
+	return params, expr, nil
+}
+
+func parseReceive(sexp Value) (ast, error) {
+	// (receive (a b) expr body)
+	head, next, ok := sexp.asCons()
+	if !ok {
+		return nil, nil
+	}
+	isReceive := parseKeyword(kw_RECEIVE, head)
+	if !isReceive {
+		return nil, nil
+	}
+	head1, next, ok := next.asCons()
+	if !ok {
+		return nil, errors.New("too few arguments to receive")
+	}
+	params, err := parseSymbols(head1)
+	if err != nil {
+		return nil, err
+	}
+	head2, next, ok := next.asCons()
+	if !ok {
+		return nil, errors.New("too few arguments to receive")
+	}
+	expr, err := parseExpr(head2)
+	if err != nil {
+		return nil, err
+	}
+	head3, next, ok := next.asCons()
+	if !ok {
+		return nil, errors.New("too few arguments to receive")
+	}
+	body, err := parseExpr(head3)
+	if err != nil {
+		return nil, err
+	}
+	if !next.isEmpty() {
+		return nil, errors.New("too many arguments to receive")
+	}
+	return makeCallWithValues(params, expr, body), nil
+}
+
+func parseBindings(sexp Value) ([]*vSymbol, []Value, []ast, error) {
+	// patterns[i] is nil when params[i] is a plain name
+	params := make([]*vSymbol, 0)
+	patterns := make([]Value, 0)
 	bindings := make([]ast, 0)
 	current := sexp
 	for head, next, ok := sexp.asCons(); ok; head, next, ok = next.asCons() {
 		headB, nextB, ok := head.asCons()
 		if !ok {
-			return nil, nil, errors.New("expected binding (name expr)")
+			return nil, nil, nil, errors.New("expected binding (name expr)")
 		}
-		name, ok := headB.asSymbol()
-		if !ok {
-			return nil, nil, errors.New("expected name in binding")
+		name, pattern, err := parsePattern(headB)
+		if err != nil {
+			return nil, nil, nil, err
 		}
 		params = append(params, name)
+		patterns = append(patterns, pattern)
 		headB2, nextB, ok := nextB.asCons()
 		if !ok {
-			return nil, nil, errors.New("expected expr in binding")
+			return nil, nil, nil, errors.New("expected expr in binding")
 		}
 		if !nextB.isEmpty() {
-			return nil, nil, errors.New("too many elements in binding")
+			return nil, nil, nil, errors.New("too many elements in binding")
 		}
 		binding, err := parseExpr(headB2)
 		if err != nil {
-			return nil, nil, err
+			return nil, nil, nil, err
 		}
 		bindings = append(bindings, binding)
 		current = next
 	}
 	if !current.isEmpty() {
-		return nil, nil, errors.New("malformed binding list")
+		return nil, nil, nil, errors.New("malformed binding list")
 	}
-	return params, bindings, nil
+	return params, patterns, bindings, nil
 }
 
-func parseFunBindings(sexp Value) ([]string, [][]string, []ast, error) {
-	names := make([]string, 0)
-	params := make([][]string, 0)
+func parseFunBindings(sexp Value) ([]*vSymbol, [][]*vSymbol, [][]*vSymbol, []ast, error) {
+	names := make([]*vSymbol, 0)
+	params := make([][]*vSymbol, 0)
+	keys := make([][]*vSymbol, 0)
 	bodies := make([]ast, 0)
 	current := sexp
 	for head, next, ok := sexp.asCons(); ok; head, next, ok = next.asCons() {
 		headB, nextB, ok := head.asCons()
 		if !ok {
-			return nil, nil, nil, errors.New("expected binding (name params expr)")
+			return nil, nil, nil, nil, errors.New("expected binding (name params expr)")
 		}
-		name, ok := headB.asSymbol()
+		name, ok := headB.(*vSymbol)
 		if !ok {
-			return nil, nil, nil, errors.New("expected name in binding")
+			return nil, nil, nil, nil, errors.New("expected name in binding")
 		}
 		names = append(names, name)
 		headB2, nextB, ok := nextB.asCons()
 		if !ok {
-			return nil, nil, nil, errors.New("expected params in binding")
+			return nil, nil, nil, nil, errors.New("expected params in binding")
 		}
-		these_params, err := parseSymbols(headB2)
+		these_params, these_keys, these_patterns, err := parseParams(headB2)
 		if err != nil {
-			return nil, nil, nil, err
+			return nil, nil, nil, nil, err
 		}
 		params = append(params, these_params)
+		keys = append(keys, these_keys)
 		headB3, nextB, ok := nextB.asCons()
 		if !ok {
-			return nil, nil, nil, errors.New("expected expr in binding")
+			return nil, nil, nil, nil, errors.New("expected expr in binding")
 		}
 		if !nextB.isEmpty() {
-			return nil, nil, nil, errors.New("too many elements in binding")
+			return nil, nil, nil, nil, errors.New("too many elements in binding")
 		}
 		body, err := parseExpr(headB3)
 		if err != nil {
-			return nil, nil, nil, err
+			return nil, nil, nil, nil, err
 		}
-		bodies = append(bodies, body)
+		bodies = append(bodies, destructurePatterns(these_params, these_patterns, body))
 		current = next
 	}
 	if !current.isEmpty() {
-		return nil, nil, nil, errors.New("malformed binding list")
+		return nil, nil, nil, nil, errors.New("malformed binding list")
 	}
-	return names, params, bodies, nil
+	return names, params, keys, bodies, nil
 }
 
-func makeLet(params []string, bindings []ast, body ast) ast {
+func makeLet(params []*vSymbol, bindings []ast, body ast) ast {
 	return &astApply{makeFunction(params, body), bindings}
 }
 
-func makeLetStar(params []string, bindings []ast, body ast) ast {
+func makeLetStar(params []*vSymbol, patterns []Value, bindings []ast, body ast) ast {
 	result := body
 	for i := len(params) - 1; i >= 0; i-- {
-		result = makeLet([]string{params[i]}, []ast{bindings[i]}, result)
+		result = destructurePatterns(params[i:i+1], patterns[i:i+1], result)
+		result = makeLet([]*vSymbol{params[i]}, []ast{bindings[i]}, result)
 	}
 	return result
 }
 
-func makeFunction(params []string, body ast) ast {
-	name := fresh("__temp")
-	return &astLetRec{[]string{name}, [][]string{params}, []ast{body}, &astId{name}}
+func makeWithEnv(names []*vSymbol, values []ast, body ast) ast {
+	args := []ast{makeFunction([]*vSymbol{}, body)}
+	for i, name := range names {
+		args = append(args, &astLiteral{&vString{name.name}}, values[i])
+	}
+	return &astApply{&astPrimitive{intern("call-with-env")}, args}
+}
+
+func makeFunction(params []*vSymbol, body ast) ast {
+	return makeRecFunction(fresh("__temp"), params, nil, body)
 }
 
-func makeRecFunction(recName string, params []string, body ast) ast {
-	return &astLetRec{[]string{recName}, [][]string{params}, []ast{body}, &astId{recName}}
+func makeRecFunction(recName *vSymbol, params []*vSymbol, keys []*vSymbol, body ast) ast {
+	return &astLetRec{[]*vSymbol{recName}, [][]*vSymbol{params}, [][]*vSymbol{keys}, []ast{body}, &astId{recName}}
 }
 
 func parseastApply(sexp Value) (ast, error) {
@@ -515,11 +856,11 @@ func parseExprs(sexp Value) ([]ast, error) {
 	return args, nil
 }
 
-func parseSymbols(sexp Value) ([]string, error) {
-	params := make([]string, 0)
+func parseSymbols(sexp Value) ([]*vSymbol, error) {
+	params := make([]*vSymbol, 0)
 	current := sexp
 	for head, next, ok := sexp.asCons(); ok; head, next, ok = next.asCons() {
-		name, ok := head.asSymbol()
+		name, ok := head.(*vSymbol)
 		if !ok {
 			return nil, errors.New("expected symbol in list")
 		}
@@ -532,6 +873,48 @@ func parseSymbols(sexp Value) ([]string, error) {
 	return params, nil
 }
 
+func parseNames(sexp Value) ([]string, error) {
+	syms, err := parseSymbols(sexp)
+	if err != nil {
+		return nil, err
+	}
+	return symbolNames(syms), nil
+}
+
+func parseParams(sexp Value) ([]*vSymbol, []*vSymbol, []Value, error) {
+	// (a [b c] &key d e): patterns, then keyword parameters after &key
+	params := make([]*vSymbol, 0)
+	keys := make([]*vSymbol, 0)
+	patterns := make([]Value, 0)
+	inKeys := false
+	current := sexp
+	for head, next, ok := sexp.asCons(); ok; head, next, ok = next.asCons() {
+		current = next
+		if parseKeyword(kw_KEY, head) && !inKeys {
+			inKeys = true
+			continue
+		}
+		if inKeys {
+			name, ok := head.(*vSymbol)
+			if !ok || name.name == kw_KEY {
+				return nil, nil, nil, errors.New("expected name after &key in parameter list")
+			}
+			keys = append(keys, name)
+			continue
+		}
+		name, pattern, err := parsePattern(head)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		params = append(params, name)
+		patterns = append(patterns, pattern)
+	}
+	if !current.isEmpty() {
+		return nil, nil, nil, errors.New("malformed parameter list")
+	}
+	return params, keys, patterns, nil
+}
+
 func parseDo(sexp Value) (ast, error) {
 	head, next, ok := sexp.asCons()
 	if !ok {
@@ -552,7 +935,7 @@ func makeDo(exprs []ast) ast {
 	if len(exprs) > 0 {
 		result := exprs[len(exprs)-1]
 		for i := len(exprs) - 2; i >= 0; i-- {
-			result = makeLet([]string{fresh("__temp")}, []ast{exprs[i]}, result)
+			result = makeLet([]*vSymbol{fresh("__temp")}, []ast{exprs[i]}, result)
 		}
 		return result
 	}
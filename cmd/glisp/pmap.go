@@ -0,0 +1,231 @@
+package main
+
+import "hash/fnv"
+import "math/bits"
+
+// A persistent hash map is a hash array mapped trie: each node uses 5 bits
+// of the key's hash to pick a slot, and a bitmap records which slots are
+// present so that nodes only store those. Keys whose hashes agree on all
+// bits end up together in a collision node. Keys are identified by their
+// canonical hash key, as for dicts.
+
+const HAMT_BITS = 5
+const HAMT_MAX_SHIFT = 30
+
+type hamtEntry struct {
+	hash  uint32
+	id    string
+	key   Value
+	value Value
+}
+
+type hamtSlot struct {
+	entry *hamtEntry
+	node  *hamtNode
+}
+
+type hamtNode struct {
+	bitmap     uint32
+	slots      []hamtSlot
+	collisions []*hamtEntry // for nodes past the last level
+}
+
+type pmap struct {
+	count int
+	root  *hamtNode
+}
+
+func emptyPMap() *pmap {
+	return &pmap{0, &hamtNode{}}
+}
+
+func newHamtEntry(key Value, value Value) (*hamtEntry, error) {
+	id, ok := hashKey(key)
+	if !ok {
+		return nil, dictKeyError(key)
+	}
+	h := fnv.New32a()
+	h.Write([]byte(id))
+	return &hamtEntry{h.Sum32(), id, key, value}, nil
+}
+
+func (m *pmap) get(key Value) (Value, bool, error) {
+	probe, err := newHamtEntry(key, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	if e := m.root.find(probe, 0); e != nil {
+		return e.value, true, nil
+	}
+	return nil, false, nil
+}
+
+func (m *pmap) assoc(key Value, value Value) (*pmap, error) {
+	e, err := newHamtEntry(key, value)
+	if err != nil {
+		return nil, err
+	}
+	root, added := m.root.assoc(e, 0)
+	count := m.count
+	if added {
+		count++
+	}
+	return &pmap{count, root}, nil
+}
+
+func (m *pmap) dissoc(key Value) (*pmap, error) {
+	probe, err := newHamtEntry(key, nil)
+	if err != nil {
+		return nil, err
+	}
+	root, removed := m.root.dissoc(probe, 0)
+	if !removed {
+		return m, nil
+	}
+	if root == nil {
+		root = &hamtNode{}
+	}
+	return &pmap{m.count - 1, root}, nil
+}
+
+func (m *pmap) entries() []*hamtEntry {
+	result := make([]*hamtEntry, 0, m.count)
+	m.root.each(func(e *hamtEntry) {
+		result = append(result, e)
+	})
+	return result
+}
+
+func (m *pmap) pairs() []Value {
+	result := make([]Value, 0, m.count)
+	for _, e := range m.entries() {
+		result = append(result, listFromSlice([]Value{e.key, e.value}))
+	}
+	return result
+}
+
+func hamtIndex(bitmap uint32, bit uint32) int {
+	return bits.OnesCount32(bitmap & (bit - 1))
+}
+
+func (n *hamtNode) find(probe *hamtEntry, shift uint) *hamtEntry {
+	if shift > HAMT_MAX_SHIFT {
+		for _, e := range n.collisions {
+			if e.id == probe.id {
+				return e
+			}
+		}
+		return nil
+	}
+	bit := uint32(1) << ((probe.hash >> shift) & 31)
+	if n.bitmap&bit == 0 {
+		return nil
+	}
+	slot := n.slots[hamtIndex(n.bitmap, bit)]
+	if slot.node != nil {
+		return slot.node.find(probe, shift+HAMT_BITS)
+	}
+	if slot.entry.id == probe.id {
+		return slot.entry
+	}
+	return nil
+}
+
+func (n *hamtNode) assoc(e *hamtEntry, shift uint) (*hamtNode, bool) {
+	if shift > HAMT_MAX_SHIFT {
+		collisions := make([]*hamtEntry, len(n.collisions), len(n.collisions)+1)
+		copy(collisions, n.collisions)
+		for i, c := range collisions {
+			if c.id == e.id {
+				collisions[i] = e
+				return &hamtNode{collisions: collisions}, false
+			}
+		}
+		return &hamtNode{collisions: append(collisions, e)}, true
+	}
+	bit := uint32(1) << ((e.hash >> shift) & 31)
+	idx := hamtIndex(n.bitmap, bit)
+	if n.bitmap&bit == 0 {
+		slots := make([]hamtSlot, len(n.slots)+1)
+		copy(slots, n.slots[:idx])
+		slots[idx] = hamtSlot{entry: e}
+		copy(slots[idx+1:], n.slots[idx:])
+		return &hamtNode{bitmap: n.bitmap | bit, slots: slots}, true
+	}
+	slots := make([]hamtSlot, len(n.slots))
+	copy(slots, n.slots)
+	slot := slots[idx]
+	added := false
+	if slot.node != nil {
+		var child *hamtNode
+		child, added = slot.node.assoc(e, shift+HAMT_BITS)
+		slots[idx] = hamtSlot{node: child}
+	} else if slot.entry.id == e.id {
+		slots[idx] = hamtSlot{entry: e}
+	} else {
+		// two keys share this slot: push both one level down
+		child, _ := (&hamtNode{}).assoc(slot.entry, shift+HAMT_BITS)
+		child, _ = child.assoc(e, shift+HAMT_BITS)
+		slots[idx] = hamtSlot{node: child}
+		added = true
+	}
+	return &hamtNode{bitmap: n.bitmap, slots: slots}, added
+}
+
+func (n *hamtNode) dissoc(probe *hamtEntry, shift uint) (*hamtNode, bool) {
+	// returns nil for a node left empty
+	if shift > HAMT_MAX_SHIFT {
+		for i, c := range n.collisions {
+			if c.id == probe.id {
+				if len(n.collisions) == 1 {
+					return nil, true
+				}
+				collisions := make([]*hamtEntry, 0, len(n.collisions)-1)
+				collisions = append(collisions, n.collisions[:i]...)
+				collisions = append(collisions, n.collisions[i+1:]...)
+				return &hamtNode{collisions: collisions}, true
+			}
+		}
+		return n, false
+	}
+	bit := uint32(1) << ((probe.hash >> shift) & 31)
+	if n.bitmap&bit == 0 {
+		return n, false
+	}
+	idx := hamtIndex(n.bitmap, bit)
+	slot := n.slots[idx]
+	if slot.node != nil {
+		child, removed := slot.node.dissoc(probe, shift+HAMT_BITS)
+		if !removed {
+			return n, false
+		}
+		if child != nil {
+			slots := make([]hamtSlot, len(n.slots))
+			copy(slots, n.slots)
+			slots[idx] = hamtSlot{node: child}
+			return &hamtNode{bitmap: n.bitmap, slots: slots}, true
+		}
+	} else if slot.entry.id != probe.id {
+		return n, false
+	}
+	if len(n.slots) == 1 {
+		return nil, true
+	}
+	slots := make([]hamtSlot, 0, len(n.slots)-1)
+	slots = append(slots, n.slots[:idx]...)
+	slots = append(slots, n.slots[idx+1:]...)
+	return &hamtNode{bitmap: n.bitmap &^ bit, slots: slots}, true
+}
+
+func (n *hamtNode) each(f func(*hamtEntry)) {
+	for _, e := range n.collisions {
+		f(e)
+	}
+	for _, slot := range n.slots {
+		if slot.node != nil {
+			slot.node.each(f)
+		} else {
+			f(slot.entry)
+		}
+	}
+}
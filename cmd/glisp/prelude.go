@@ -0,0 +1,38 @@
+package main
+
+import _ "embed"
+import "sync"
+
+// The standard prelude is glisp code evaluated into the core environment
+// of every engine. It is parsed once per process and the parsed forms
+// are shared by all engines.
+
+//go:embed prelude.glisp
+var PRELUDE string
+
+var preludeOnce sync.Once
+var preludeForms []*topLevel
+var preludeErr error
+
+func defaultPrelude() ([]*topLevel, error) {
+	preludeOnce.Do(func() {
+		preludeForms, preludeErr = parsePrelude(PRELUDE)
+	})
+	return preludeForms, preludeErr
+}
+
+func parsePrelude(src string) ([]*topLevel, error) {
+	sexps, err := readAll(src)
+	if err != nil {
+		return nil, &topLevelError{"READ", err}
+	}
+	forms := make([]*topLevel, len(sexps))
+	for i, sexp := range sexps {
+		top, err := parseTop(sexp)
+		if err != nil {
+			return nil, err
+		}
+		forms[i] = top
+	}
+	return forms, nil
+}
@@ -0,0 +1,54 @@
+package main
+
+import "fmt"
+import "strings"
+import "testing"
+
+func TestPrelude(t *testing.T) {
+	checkEval(t, `(identity 42)`, "42")
+	checkEval(t, `((constantly 1) 2)`, "1")
+	checkEval(t, `((compose inc dec) 5)`, "5")
+	checkEval(t, `((flip -) 1 10)`, "9")
+	checkEval(t, `(abs (- 3))`, "3")
+	checkEval(t, `(max2 3 7)`, "7")
+	checkEval(t, `(second '(1 2 3))`, "2")
+	checkEval(t, `(sum '(1 2 3 4))`, "10")
+	checkEval(t, `(product '(1 2 3 4))`, "24")
+	checkEval(t, `(count zero? '(0 1 0 2))`, "2")
+	checkEval(t, `(mapcat (fn (x) (list x x)) '(1 2))`, "(1 1 2 2)")
+	checkEval(t, `(string-blank? "   ")`, "#t")
+	checkEval(t, `(string-unwords (list "a" "b"))`, `"a b"`)
+}
+
+func TestPreludeIsOverridable(t *testing.T) {
+	// a global definition shadows the prelude's but modules still see the original
+	dir := writeModules(t, map[string]string{
+		"m.glisp": "(module m (export f))\n(def (f x) (inc x))\n",
+	})
+	checkEval(t, `(def (inc n) (+ n 10)) (inc 1)`, "11")
+	checkEval(t, fmt.Sprintf(`(cd %q) (def (inc n) (+ n 10)) (import m) (m/f 1)`, dir), "2")
+}
+
+func TestEngineWithPrelude(t *testing.T) {
+	e, err := NewEngineWithPrelude(`(def (triple x) (* 3 x))`)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err.Error())
+	}
+	v, err := evalSource(e, `(triple 4)`)
+	if err != nil || v.Display() != "12" {
+		t.Errorf("expected 12 but got %v, %v", v, err)
+	}
+	if _, err := evalSource(e, `(inc 1)`); err == nil {
+		t.Errorf("expected the standard prelude to be absent")
+	}
+	empty, err := NewEngineWithPrelude(``)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err.Error())
+	}
+	if _, err := evalSource(empty, `(identity 1)`); err == nil {
+		t.Errorf("expected an empty prelude to opt out of the standard prelude")
+	}
+	if _, err := NewEngineWithPrelude(`(def (f x) (g x)) (f 1)`); err == nil || !strings.Contains(err.Error(), "g") {
+		t.Errorf("expected a failing prelude to report its error but got %v", err)
+	}
+}
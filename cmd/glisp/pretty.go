@@ -0,0 +1,196 @@
+package main
+
+import "os"
+import "strconv"
+import "strings"
+import "unicode/utf8"
+
+// Pretty printing follows Wadler's "prettier printer": a value is first
+// turned into a document made of text, line breaks, nesting and groups,
+// and a group is printed flat whenever it fits in the remaining width.
+
+const DEFAULT_WIDTH = 80
+
+type doc interface{}
+
+type docText string
+
+type docLine struct{} // a space when flat, a newline when broken
+
+type docConcat []doc
+
+type docNest struct {
+	indent int
+	d      doc
+}
+
+type docGroup struct {
+	d doc
+}
+
+type docCmd struct {
+	indent int
+	flat   bool
+	d      doc
+}
+
+func terminalWidth() int {
+	// $COLUMNS overrides the size of the terminal
+	if w, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && w > 0 {
+		return w
+	}
+	if w := ttyWidth(); w > 0 {
+		return w
+	}
+	return DEFAULT_WIDTH
+}
+
+func prettyDisplay(v Value, width int) string {
+	s := v.Display()
+	if utf8.RuneCountInString(s) <= width && !strings.Contains(s, "\n") {
+		return s
+	}
+	return layoutDoc(valueDoc(v), width)
+}
+
+func valueDoc(v Value) doc {
+	if _, _, ok := v.asCons(); ok {
+		items := []doc{}
+		current := v
+		for head, next, ok := v.asCons(); ok; head, next, ok = next.asCons() {
+			items = append(items, valueDoc(head))
+			current = next
+		}
+		if !current.isEmpty() {
+			items = append(items, docText("."), valueDoc(current))
+		}
+		return bracketDoc("(", items, ")")
+	}
+	if content, ok := v.asArray(); ok {
+		items := make([]doc, len(content))
+		for i, vv := range content {
+			items[i] = valueDoc(vv)
+		}
+		return bracketDoc("#[", items, "]")
+	}
+	if content, ok := v.asDict(); ok {
+		entries := content.entries()
+		items := make([]doc, len(entries))
+		for i, entry := range entries {
+			items[i] = bracketDoc("(", []doc{valueDoc(entry.key), valueDoc(entry.value)}, ")")
+		}
+		return bracketDoc("#(", items, ")")
+	}
+	if p, ok := v.(*vVector); ok {
+		items := make([]doc, p.content.count)
+		for i := range items {
+			items[i] = valueDoc(p.content.nth(i))
+		}
+		return bracketDoc("[", items, "]")
+	}
+	if m, ok := v.(*vMap); ok {
+		// keep each key next to its value
+		entries := m.content.entries()
+		items := make([]doc, len(entries))
+		for i, e := range entries {
+			items[i] = docGroup{docConcat{valueDoc(e.key), docNest{2, docConcat{docLine{}, valueDoc(e.value)}}}}
+		}
+		return bracketDoc("{", items, "}")
+	}
+	if r, ok := v.(*vRecord); ok && len(r.fields) > 0 {
+		items := make([]doc, len(r.fields))
+		for i, field := range r.fields {
+			items[i] = docConcat{docText(r.rtype.fields[i] + "="), valueDoc(field)}
+		}
+		return bracketDoc("#<"+r.rtype.name+" ", items, ">")
+	}
+	if s, ok := v.(*vSet); ok {
+		entries := s.content.entries()
+		items := make([]doc, len(entries))
+		for i, e := range entries {
+			items[i] = valueDoc(e.key)
+		}
+		return bracketDoc("#{", items, "}")
+	}
+	return docText(v.Display())
+}
+
+func bracketDoc(open string, items []doc, close string) doc {
+	body := docConcat{}
+	for i, item := range items {
+		if i > 0 {
+			body = append(body, docLine{})
+		}
+		body = append(body, item)
+	}
+	return docGroup{docConcat{docText(open), docNest{len(open), body}, docText(close)}}
+}
+
+func layoutDoc(d doc, width int) string {
+	var out strings.Builder
+	col := 0
+	stack := []docCmd{docCmd{0, false, d}}
+	for len(stack) > 0 {
+		cmd := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		switch dd := cmd.d.(type) {
+		case docText:
+			out.WriteString(string(dd))
+			col += utf8.RuneCountInString(string(dd))
+		case docLine:
+			if cmd.flat {
+				out.WriteString(" ")
+				col += 1
+			} else {
+				out.WriteString("\n" + strings.Repeat(" ", cmd.indent))
+				col = cmd.indent
+			}
+		case docConcat:
+			for i := len(dd) - 1; i >= 0; i-- {
+				stack = append(stack, docCmd{cmd.indent, cmd.flat, dd[i]})
+			}
+		case docNest:
+			stack = append(stack, docCmd{cmd.indent + dd.indent, cmd.flat, dd.d})
+		case docGroup:
+			flat := cmd.flat
+			if !flat {
+				rest := append(stack, docCmd{cmd.indent, true, dd.d})
+				flat = fits(width-col, rest)
+			}
+			stack = append(stack, docCmd{cmd.indent, flat, dd.d})
+		}
+	}
+	return out.String()
+}
+
+func fits(remaining int, cmds []docCmd) bool {
+	// check whether the text up to the next line break fits;
+	// cmds is a stack, so work on a copy from the top
+	stack := make([]docCmd, len(cmds))
+	copy(stack, cmds)
+	for remaining >= 0 {
+		if len(stack) == 0 {
+			return true
+		}
+		cmd := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		switch dd := cmd.d.(type) {
+		case docText:
+			remaining -= utf8.RuneCountInString(string(dd))
+		case docLine:
+			if !cmd.flat {
+				return true
+			}
+			remaining -= 1
+		case docConcat:
+			for i := len(dd) - 1; i >= 0; i-- {
+				stack = append(stack, docCmd{cmd.indent, cmd.flat, dd[i]})
+			}
+		case docNest:
+			stack = append(stack, docCmd{cmd.indent + dd.indent, cmd.flat, dd.d})
+		case docGroup:
+			stack = append(stack, docCmd{cmd.indent, cmd.flat, dd.d})
+		}
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package main
+
+import "testing"
+
+func TestPrettyPrint(t *testing.T) {
+	// groups are printed flat when they fit and broken one item per line
+	// otherwise, nested one column in from their bracket
+	v, _, err := read("(def (fact n) (if (= n 0) 1 (* n (fact (- n 1)))))")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for width, expected := range map[int]string{
+		80: "(def (fact n) (if (= n 0) 1 (* n (fact (- n 1)))))",
+		20: "(def\n (fact n)\n (if\n  (= n 0)\n  1\n  (*\n   n\n   (fact (- n 1)))))",
+		10: "(def\n (fact n)\n (if\n  (= n 0)\n  1\n  (*\n   n\n   (fact\n    (-\n     n\n     1)))))",
+	} {
+		if pretty := prettyDisplay(v, width); pretty != expected {
+			t.Errorf("width %d - expected %q but got %q", width, expected, pretty)
+		}
+	}
+}
+
+func TestTerminalWidth(t *testing.T) {
+	t.Setenv("COLUMNS", "42")
+	if w := terminalWidth(); w != 42 {
+		t.Errorf("expected width 42 from $COLUMNS but got %d", w)
+	}
+}
+
+func TestPrettyRecords(t *testing.T) {
+	// a width of 0 forces the layout, which breaks between fields
+	for src, expected := range map[string]string{
+		`(defrecord empty ()) (make-empty)`:        "#<empty>",
+		`(defrecord point (x y)) (make-point 1 2)`: "#<point x=1\n        y=2>",
+	} {
+		v, err := evalSource(NewEngine(), src)
+		if err != nil {
+			t.Errorf("%s - unexpected error %s", src, err.Error())
+			continue
+		}
+		if pretty := prettyDisplay(v, 0); pretty != expected {
+			t.Errorf("%s - expected %q but got %q", src, expected, pretty)
+		}
+	}
+}
@@ -1,7 +1,10 @@
 package main
 
 import "fmt"
+import "strconv"
 import "strings"
+import "unicode"
+import "unicode/utf8"
 
 type Primitive struct {
 	name string
@@ -18,9 +21,11 @@ func listLength(v Value) int {
 	return result
 }
 
-func listAppend(v1 Value, v2 Value) Value {
+func listAppend(v1 Value, v2 Value) (Value, bool) {
+	// false if v1 is not a proper list
 	var result Value = nil
 	var current_result MutableCons = nil
+	current := v1
 	for head, next, ok := v1.asCons(); ok; head, next, ok = next.asCons() {
 		cell := NewMutableCons(head, nil)
 		if current_result == nil {
@@ -29,27 +34,40 @@ func listAppend(v1 Value, v2 Value) Value {
 			current_result.setTail(cell)
 		}
 		current_result = cell
+		current = next
+	}
+	if !current.isEmpty() {
+		return nil, false
 	}
 	if current_result == nil {
-		return v2
+		return v2, true
 	}
 	current_result.setTail(v2)
+	return result, true
+}
+
+func listFromSlice(vs []Value) Value {
+	var result Value = NewEmpty()
+	for i := len(vs) - 1; i >= 0; i -= 1 {
+		result = NewCons(vs[i], result)
+	}
 	return result
 }
 
-func allConses(vs []Value) bool {
-	for _, v := range vs {
-		if _, _, ok := v.asCons(); !ok {
-			return false
-		}
+func stringsToList(strs []string) Value {
+	vs := make([]Value, len(strs))
+	for i, str := range strs {
+		vs[i] = NewString(str)
 	}
-	return true
+	return listFromSlice(vs)
 }
 
-func corePrimitives() map[string]Value {
-	bindings := map[string]Value{}
-	for _, d := range CORE_PRIMITIVES {
-		bindings[d.name] = NewPrimitive(d.name, MakePrimitive(d))
+func corePrimitives(st *engineState) map[*vSymbol]Value {
+	bindings := map[*vSymbol]Value{}
+	for _, prims := range [][]Primitive{CORE_PRIMITIVES, LIST_PRIMITIVES, ARRAY_PRIMITIVES, DICT_PRIMITIVES, PERSISTENT_PRIMITIVES, SET_PRIMITIVES, LAZY_PRIMITIVES, REGEX_PRIMITIVES, fsPrimitives(st), envPrimitives(st), portPrimitives(st), commandPrimitives(st)} {
+		for _, d := range prims {
+			bindings[intern(d.name)] = NewPrimitive(d.name, MakePrimitive(d))
+		}
 	}
 	return bindings
 }
@@ -142,6 +160,63 @@ func mkNumPredicate(pred func(int, int) bool) func(string, []Value) (Value, erro
 	}
 }
 
+func mkStringPredicate(pred func(string, string) bool) func(string, []Value) (Value, error) {
+	return func(name string, args []Value) (Value, error) {
+		str1, ok := args[0].asString()
+		if err := checkArgTypeB(name, args[0], ok); err != nil {
+			return nil, err
+		}
+		str2, ok := args[1].asString()
+		if err := checkArgTypeB(name, args[1], ok); err != nil {
+			return nil, err
+		}
+		return NewBoolean(pred(str1, str2)), nil
+	}
+}
+
+func radixArg(name string, args []Value) (int, error) {
+	// optional radix as second argument, defaulting to 10
+	if len(args) < 2 {
+		return 10, nil
+	}
+	radix, ok := args[1].asInteger()
+	if err := checkArgTypeB(name, args[1], ok); err != nil {
+		return 0, err
+	}
+	if radix < 2 || radix > 36 {
+		return 0, fmt.Errorf("%s - invalid radix %d", name, radix)
+	}
+	return radix, nil
+}
+
+func mkCharPredicate(pred func(rune) bool) func(string, []Value) (Value, error) {
+	return func(name string, args []Value) (Value, error) {
+		r, ok := args[0].asChar()
+		if err := checkArgTypeB(name, args[0], ok); err != nil {
+			return nil, err
+		}
+		return NewBoolean(pred(r)), nil
+	}
+}
+
+func mkStringTrim(trimSpace func(string) string, trimCutset func(string, string) string) func(string, []Value) (Value, error) {
+	// without a cutset, trim whitespace
+	return func(name string, args []Value) (Value, error) {
+		str, ok := args[0].asString()
+		if err := checkArgTypeB(name, args[0], ok); err != nil {
+			return nil, err
+		}
+		if len(args) < 2 {
+			return NewString(trimSpace(str)), nil
+		}
+		cutset, ok := args[1].asString()
+		if err := checkArgTypeB(name, args[1], ok); err != nil {
+			return nil, err
+		}
+		return NewString(trimCutset(str, cutset)), nil
+	}
+}
+
 var CORE_PRIMITIVES = []Primitive{
 
 	Primitive{
@@ -215,6 +290,26 @@ var CORE_PRIMITIVES = []Primitive{
 		},
 	},
 
+	Primitive{"eq?", 2, 2,
+		func(name string, args []Value) (Value, error) {
+			// identity on mutable containers, value equality otherwise
+			return NewBoolean(identical(args[0], args[1])), nil
+		},
+	},
+
+	Primitive{"equal?", 2, 2,
+		func(name string, args []Value) (Value, error) {
+			return NewBoolean(equalValues(args[0], args[1])), nil
+		},
+	},
+
+	Primitive{"hash", 1, 1,
+		func(name string, args []Value) (Value, error) {
+			// values that are equal? have the same hash
+			return NewInteger(hashValue(args[0])), nil
+		},
+	},
+
 	Primitive{"<", 2, 2,
 		mkNumPredicate(func(n1 int, n2 int) bool { return n1 < n2 }),
 	},
@@ -258,7 +353,7 @@ var CORE_PRIMITIVES = []Primitive{
 			if err := checkArgTypeB(name, args[0], ok); err != nil {
 				return nil, err
 			}
-			return NewInteger(len(str)), nil
+			return NewInteger(utf8.RuneCountInString(str)), nil
 		},
 	},
 
@@ -288,8 +383,9 @@ var CORE_PRIMITIVES = []Primitive{
 			if err := checkArgTypeB(name, args[0], ok); err != nil {
 				return nil, err
 			}
+			runes := []rune(str)
 			start := 0
-			end := len(str)
+			end := len(runes)
 			if len(args) > 2 {
 				i1, ok := args[2].asInteger()
 				if err := checkArgTypeB(name, args[2], ok); err != nil {
@@ -308,7 +404,369 @@ var CORE_PRIMITIVES = []Primitive{
 			if end < start {
 				return NewString(""), nil
 			}
-			return NewString(str[start:end]), nil
+			return NewString(string(runes[start:end])), nil
+		},
+	},
+
+	Primitive{"string-ref", 2, 2,
+		func(name string, args []Value) (Value, error) {
+			str, ok := args[0].asString()
+			if err := checkArgTypeB(name, args[0], ok); err != nil {
+				return nil, err
+			}
+			idx, ok := args[1].asInteger()
+			if err := checkArgTypeB(name, args[1], ok); err != nil {
+				return nil, err
+			}
+			runes := []rune(str)
+			if idx < 0 || idx >= len(runes) {
+				return nil, fmt.Errorf("%s - index %d out of bound", name, idx)
+			}
+			return NewChar(runes[idx]), nil
+		},
+	},
+
+	Primitive{"string->list", 1, 1,
+		func(name string, args []Value) (Value, error) {
+			str, ok := args[0].asString()
+			if err := checkArgTypeB(name, args[0], ok); err != nil {
+				return nil, err
+			}
+			chars := []Value{}
+			for _, r := range str {
+				chars = append(chars, NewChar(r))
+			}
+			return listFromSlice(chars), nil
+		},
+	},
+
+	Primitive{"list->string", 1, 1,
+		func(name string, args []Value) (Value, error) {
+			if err := checkArgType(name, args[0], isList); err != nil {
+				return nil, err
+			}
+			var b strings.Builder
+			current := args[0]
+			for head, next, ok := args[0].asCons(); ok; head, next, ok = next.asCons() {
+				r, ok := head.asChar()
+				if err := checkArgTypeB(name, head, ok); err != nil {
+					return nil, err
+				}
+				b.WriteRune(r)
+				current = next
+			}
+			if !current.isEmpty() {
+				return nil, fmt.Errorf("%s - malformed list", name)
+			}
+			return NewString(b.String()), nil
+		},
+	},
+
+	Primitive{"char->integer", 1, 1,
+		func(name string, args []Value) (Value, error) {
+			r, ok := args[0].asChar()
+			if err := checkArgTypeB(name, args[0], ok); err != nil {
+				return nil, err
+			}
+			return NewInteger(int(r)), nil
+		},
+	},
+
+	Primitive{"integer->char", 1, 1,
+		func(name string, args []Value) (Value, error) {
+			i, ok := args[0].asInteger()
+			if err := checkArgTypeB(name, args[0], ok); err != nil {
+				return nil, err
+			}
+			if i < 0 || i > unicode.MaxRune || !utf8.ValidRune(rune(i)) {
+				return nil, fmt.Errorf("%s - invalid code point %d", name, i)
+			}
+			return NewChar(rune(i)), nil
+		},
+	},
+
+	Primitive{"char-letter?", 1, 1,
+		mkCharPredicate(unicode.IsLetter),
+	},
+
+	Primitive{"char-digit?", 1, 1,
+		mkCharPredicate(unicode.IsDigit),
+	},
+
+	Primitive{"char-space?", 1, 1,
+		mkCharPredicate(unicode.IsSpace),
+	},
+
+	Primitive{"char-upper?", 1, 1,
+		mkCharPredicate(unicode.IsUpper),
+	},
+
+	Primitive{"char-lower?", 1, 1,
+		mkCharPredicate(unicode.IsLower),
+	},
+
+	Primitive{"char-upcase", 1, 1,
+		func(name string, args []Value) (Value, error) {
+			r, ok := args[0].asChar()
+			if err := checkArgTypeB(name, args[0], ok); err != nil {
+				return nil, err
+			}
+			return NewChar(unicode.ToUpper(r)), nil
+		},
+	},
+
+	Primitive{"char-downcase", 1, 1,
+		func(name string, args []Value) (Value, error) {
+			r, ok := args[0].asChar()
+			if err := checkArgTypeB(name, args[0], ok); err != nil {
+				return nil, err
+			}
+			return NewChar(unicode.ToLower(r)), nil
+		},
+	},
+
+	Primitive{"string-split", 1, 2,
+		func(name string, args []Value) (Value, error) {
+			str, ok := args[0].asString()
+			if err := checkArgTypeB(name, args[0], ok); err != nil {
+				return nil, err
+			}
+			if len(args) < 2 {
+				return stringsToList(strings.Fields(str)), nil
+			}
+			sep, ok := args[1].asString()
+			if err := checkArgTypeB(name, args[1], ok); err != nil {
+				return nil, err
+			}
+			return stringsToList(strings.Split(str, sep)), nil
+		},
+	},
+
+	Primitive{"string-lines", 1, 1,
+		func(name string, args []Value) (Value, error) {
+			str, ok := args[0].asString()
+			if err := checkArgTypeB(name, args[0], ok); err != nil {
+				return nil, err
+			}
+			str = strings.TrimSuffix(strings.ReplaceAll(str, "\r\n", "\n"), "\n")
+			if str == "" {
+				return NewEmpty(), nil
+			}
+			return stringsToList(strings.Split(str, "\n")), nil
+		},
+	},
+
+	Primitive{"string-join", 1, 2,
+		func(name string, args []Value) (Value, error) {
+			items, err := seqToSlice(name, args[0])
+			if err != nil {
+				return nil, err
+			}
+			sep := ""
+			if len(args) > 1 {
+				s, ok := args[1].asString()
+				if err := checkArgTypeB(name, args[1], ok); err != nil {
+					return nil, err
+				}
+				sep = s
+			}
+			strs := make([]string, len(items))
+			for i, item := range items {
+				str, ok := item.asString()
+				if err := checkArgTypeB(name, item, ok); err != nil {
+					return nil, err
+				}
+				strs[i] = str
+			}
+			return NewString(strings.Join(strs, sep)), nil
+		},
+	},
+
+	Primitive{"string-trim", 1, 2,
+		mkStringTrim(strings.TrimSpace, strings.Trim),
+	},
+
+	Primitive{"string-trim-left", 1, 2,
+		mkStringTrim(
+			func(s string) string { return strings.TrimLeftFunc(s, unicode.IsSpace) },
+			strings.TrimLeft),
+	},
+
+	Primitive{"string-trim-right", 1, 2,
+		mkStringTrim(
+			func(s string) string { return strings.TrimRightFunc(s, unicode.IsSpace) },
+			strings.TrimRight),
+	},
+
+	Primitive{"string-index", 2, 2,
+		func(name string, args []Value) (Value, error) {
+			str, ok := args[0].asString()
+			if err := checkArgTypeB(name, args[0], ok); err != nil {
+				return nil, err
+			}
+			sub, ok := args[1].asString()
+			if err := checkArgTypeB(name, args[1], ok); err != nil {
+				return nil, err
+			}
+			idx := strings.Index(str, sub)
+			if idx < 0 {
+				return NewBoolean(false), nil
+			}
+			return NewInteger(utf8.RuneCountInString(str[:idx])), nil
+		},
+	},
+
+	Primitive{"string-contains?", 2, 2,
+		mkStringPredicate(strings.Contains),
+	},
+
+	Primitive{"string-prefix?", 2, 2,
+		mkStringPredicate(strings.HasPrefix),
+	},
+
+	Primitive{"string-suffix?", 2, 2,
+		mkStringPredicate(strings.HasSuffix),
+	},
+
+	Primitive{"string-replace", 3, 4,
+		func(name string, args []Value) (Value, error) {
+			strs := make([]string, 3)
+			for i := range strs {
+				str, ok := args[i].asString()
+				if err := checkArgTypeB(name, args[i], ok); err != nil {
+					return nil, err
+				}
+				strs[i] = str
+			}
+			count := -1
+			if len(args) > 3 {
+				n, ok := args[3].asInteger()
+				if err := checkArgTypeB(name, args[3], ok); err != nil {
+					return nil, err
+				}
+				count = n
+			}
+			return NewString(strings.Replace(strs[0], strs[1], strs[2], count)), nil
+		},
+	},
+
+	Primitive{"string-repeat", 2, 2,
+		func(name string, args []Value) (Value, error) {
+			str, ok := args[0].asString()
+			if err := checkArgTypeB(name, args[0], ok); err != nil {
+				return nil, err
+			}
+			count, ok := args[1].asInteger()
+			if err := checkArgTypeB(name, args[1], ok); err != nil {
+				return nil, err
+			}
+			if count < 0 {
+				return nil, fmt.Errorf("%s - negative count %d", name, count)
+			}
+			return NewString(strings.Repeat(str, count)), nil
+		},
+	},
+
+	Primitive{"string->number", 1, 2,
+		func(name string, args []Value) (Value, error) {
+			str, ok := args[0].asString()
+			if err := checkArgTypeB(name, args[0], ok); err != nil {
+				return nil, err
+			}
+			radix, err := radixArg(name, args)
+			if err != nil {
+				return nil, err
+			}
+			num, err := strconv.ParseInt(strings.TrimSpace(str), radix, 0)
+			if err != nil {
+				return NewBoolean(false), nil
+			}
+			return NewInteger(int(num)), nil
+		},
+	},
+
+	Primitive{"number->string", 1, 2,
+		func(name string, args []Value) (Value, error) {
+			num, ok := args[0].asInteger()
+			if err := checkArgTypeB(name, args[0], ok); err != nil {
+				return nil, err
+			}
+			radix, err := radixArg(name, args)
+			if err != nil {
+				return nil, err
+			}
+			return NewString(strconv.FormatInt(int64(num), radix)), nil
+		},
+	},
+
+	Primitive{"string->symbol", 1, 1,
+		func(name string, args []Value) (Value, error) {
+			str, ok := args[0].asString()
+			if err := checkArgTypeB(name, args[0], ok); err != nil {
+				return nil, err
+			}
+			if str == "" {
+				return nil, fmt.Errorf("%s - empty symbol name", name)
+			}
+			return NewSymbol(str), nil
+		},
+	},
+
+	Primitive{"symbol->string", 1, 1,
+		func(name string, args []Value) (Value, error) {
+			sym, ok := args[0].asSymbol()
+			if err := checkArgTypeB(name, args[0], ok); err != nil {
+				return nil, err
+			}
+			return NewString(sym), nil
+		},
+	},
+
+	Primitive{"string->keyword", 1, 1,
+		func(name string, args []Value) (Value, error) {
+			str, ok := args[0].asString()
+			if err := checkArgTypeB(name, args[0], ok); err != nil {
+				return nil, err
+			}
+			if str == "" {
+				return nil, fmt.Errorf("%s - empty keyword name", name)
+			}
+			return NewKeyword(str), nil
+		},
+	},
+
+	Primitive{"keyword->string", 1, 1,
+		func(name string, args []Value) (Value, error) {
+			k, ok := args[0].asKeyword()
+			if err := checkArgTypeB(name, args[0], ok); err != nil {
+				return nil, err
+			}
+			return NewString(k), nil
+		},
+	},
+
+	Primitive{"gensym", 0, 1,
+		func(name string, args []Value) (Value, error) {
+			// a fresh symbol, distinct from any symbol seen so far
+			prefix := "g"
+			if len(args) > 0 {
+				str, ok := args[0].asString()
+				if err := checkArgTypeB(name, args[0], ok); err != nil {
+					return nil, err
+				}
+				prefix = str
+			}
+			return gensym(prefix), nil
+		},
+	},
+
+	Primitive{"->string", 1, 1,
+		func(name string, args []Value) (Value, error) {
+			// strings are returned as is rather than quoted
+			if str, ok := args[0].asString(); ok {
+				return NewString(str), nil
+			}
+			return NewString(args[0].Display()), nil
 		},
 	},
 
@@ -334,15 +792,76 @@ var CORE_PRIMITIVES = []Primitive{
 		},
 	},
 
-	Primitive{"cons", 2, 2,
+	Primitive{"values", 0, -1,
 		func(name string, args []Value) (Value, error) {
-			if err := checkArgType(name, args[1], isList); err != nil {
+			content := make([]Value, len(args))
+			copy(content, args)
+			return NewValues(content), nil
+		},
+	},
+
+	Primitive{"call-with-values", 2, 2,
+		func(name string, args []Value) (Value, error) {
+			// call the consumer with the values returned by the producer
+			if err := checkArgType(name, args[0], isFunction); err != nil {
+				return nil, err
+			}
+			if err := checkArgType(name, args[1], isFunction); err != nil {
+				return nil, err
+			}
+			v, err := args[0].apply([]Value{})
+			if err != nil {
 				return nil, err
 			}
+			vs := valuesToSlice(v)
+			if f, ok := args[1].(*vFunction); ok && len(f.params) != len(vs) {
+				return nil, fmt.Errorf("%s - expected %d values but got %d", name, len(f.params), len(vs))
+			}
+			return args[1].apply(vs)
+		},
+	},
+
+	Primitive{"destructure", 2, 2,
+		func(name string, args []Value) (Value, error) {
+			// the values bound by a pattern, see destructure.go
+			if _, err := patternNames(args[0]); err != nil {
+				return nil, fmt.Errorf("%s - %s", name, err.Error())
+			}
+			vs, err := destructure(args[0], args[1])
+			if err != nil {
+				return nil, fmt.Errorf("%s - %s", name, err.Error())
+			}
+			return NewValues(vs), nil
+		},
+	},
+
+	Primitive{"cons", 2, 2,
+		func(name string, args []Value) (Value, error) {
+			// a tail that is not a list makes a dotted pair
 			return NewCons(args[0], args[1]), nil
 		},
 	},
 
+	Primitive{"car", 1, 1,
+		func(name string, args []Value) (Value, error) {
+			head, _, ok := args[0].asCons()
+			if err := checkArgTypeB(name, args[0], ok); err != nil {
+				return nil, err
+			}
+			return head, nil
+		},
+	},
+
+	Primitive{"cdr", 1, 1,
+		func(name string, args []Value) (Value, error) {
+			_, tail, ok := args[0].asCons()
+			if err := checkArgTypeB(name, args[0], ok); err != nil {
+				return nil, err
+			}
+			return tail, nil
+		},
+	},
+
 	Primitive{
 		"append", 0, -1,
 		func(name string, args []Value) (Value, error) {
@@ -357,7 +876,10 @@ var CORE_PRIMITIVES = []Primitive{
 				if err := checkArgType(name, args[i], isList); err != nil {
 					return nil, err
 				}
-				result = listAppend(args[i], result)
+				var ok bool
+				if result, ok = listAppend(args[i], result); !ok {
+					return nil, fmt.Errorf("%s - malformed list", name)
+				}
 			}
 			return result, nil
 		},
@@ -365,8 +887,15 @@ var CORE_PRIMITIVES = []Primitive{
 
 	Primitive{"reverse", 1, 1,
 		func(name string, args []Value) (Value, error) {
-			if err := checkArgType(name, args[0], isList); err != nil {
-				return nil, err
+			if !isList(args[0]) {
+				items, err := seqToSlice(name, args[0])
+				if err != nil {
+					return nil, err
+				}
+				for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+					items[i], items[j] = items[j], items[i]
+				}
+				return sameKind(args[0], items)
 			}
 			var result Value = NewEmpty()
 			current := args[0]
@@ -419,37 +948,41 @@ var CORE_PRIMITIVES = []Primitive{
 
 	Primitive{"length", 1, 1,
 		func(name string, args []Value) (Value, error) {
-			if err := checkArgType(name, args[0], isList); err != nil {
+			count, err := seqLength(name, args[0])
+			if err != nil {
 				return nil, err
 			}
-			count := 0
-			current := args[0]
-			for _, next, ok := args[0].asCons(); ok; _, next, ok = next.asCons() {
-				count += 1
-				current = next
-			}
-			if !current.isEmpty() {
-				return nil, fmt.Errorf("%s - malformed list", name)
-			}
 			return NewInteger(count), nil
 		},
 	},
 
 	Primitive{"nth", 2, 2,
 		func(name string, args []Value) (Value, error) {
-			if err := checkArgType(name, args[0], isList); err != nil {
-				return nil, err
-			}
 			idx, ok := args[1].asInteger()
 			if err := checkArgTypeB(name, args[1], ok); err != nil {
 				return nil, err
 			}
+			if content, ok := args[0].asArray(); ok {
+				if idx >= 0 && idx < len(content) {
+					return content[idx], nil
+				}
+				return nil, fmt.Errorf("%s - index %d out of bound", name, idx)
+			}
+			next, err := seqArg(name, args[0])
+			if err != nil {
+				return nil, err
+			}
 			if idx >= 0 {
-				for head, next, ok := args[0].asCons(); ok; head, next, ok = next.asCons() {
-					if idx == 0 {
-						return head, nil
-					} else {
-						idx -= 1
+				for i := 0; ; i++ {
+					v, ok, err := next()
+					if err != nil {
+						return nil, err
+					}
+					if !ok {
+						break
+					}
+					if i == idx {
+						return v, nil
 					}
 				}
 			}
@@ -459,47 +992,41 @@ var CORE_PRIMITIVES = []Primitive{
 
 	Primitive{"map", 2, -1,
 		func(name string, args []Value) (Value, error) {
+			// the result is an array when mapping over an array, a vector
+			// over a vector, a lazy sequence when any sequence is lazy,
+			// and a list otherwise
 			if err := checkArgType(name, args[0], isFunction); err != nil {
 				return nil, err
 			}
-			for i := range args[1:] {
-				if err := checkArgType(name, args[i+1], isList); err != nil {
-					return nil, err
-				}
+			nexts, err := seqArgs(name, args[1:])
+			if err != nil {
+				return nil, err
 			}
-			var result Value = nil
-			var current_result MutableCons = nil
-			currents := make([]Value, len(args)-1)
-			firsts := make([]Value, len(args)-1)
-			for i := range args[1:] {
-				currents[i] = args[i+1]
+			if anyLazy(args[1:]) {
+				return lazyMap(args[0], nexts), nil
 			}
-			for allConses(currents) {
-				for i := range currents {
-					head, _, _ := currents[i].asCons()
-					firsts[i] = head
-				}
-				v, err := args[0].apply(firsts)
+			results := []Value{}
+			for {
+				firsts, ok, err := nextAll(nexts)
 				if err != nil {
 					return nil, err
 				}
-				cell := NewMutableCons(v, nil)
-				if current_result == nil {
-					result = cell
-				} else {
-					current_result.setTail(cell)
+				if !ok {
+					break
 				}
-				current_result = cell
-				for i := range currents {
-					_, tail, _ := currents[i].asCons()
-					currents[i] = tail
+				v, err := applyFirst(args[0], firsts)
+				if err != nil {
+					return nil, err
 				}
+				results = append(results, v)
 			}
-			if current_result == nil {
-				return NewEmpty(), nil
+			if _, ok := args[1].asArray(); ok {
+				return NewArray(results), nil
 			}
-			current_result.setTail(NewEmpty())
-			return result, nil
+			if _, ok := args[1].(*vVector); ok {
+				return NewVector(results), nil
+			}
+			return listFromSlice(results), nil
 		},
 	},
 
@@ -508,70 +1035,53 @@ var CORE_PRIMITIVES = []Primitive{
 			if err := checkArgType(name, args[0], isFunction); err != nil {
 				return nil, err
 			}
-			// TODO - allow different types in the same iteration!
-			for i := range args[1:] {
-				if err := checkArgType(name, args[i+1], isList); err != nil {
-					return nil, err
-				}
-			}
-			currents := make([]Value, len(args)-1)
-			firsts := make([]Value, len(args)-1)
-			for i := range args[1:] {
-				currents[i] = args[i+1]
+			nexts, err := seqArgs(name, args[1:])
+			if err != nil {
+				return nil, err
 			}
-			for allConses(currents) {
-				for i := range currents {
-					head, _, _ := currents[i].asCons()
-					firsts[i] = head
-				}
-				_, err := args[0].apply(firsts)
+			for {
+				firsts, ok, err := nextAll(nexts)
 				if err != nil {
 					return nil, err
 				}
-				for i := range currents {
-					_, tail, _ := currents[i].asCons()
-					currents[i] = tail
+				if !ok {
+					return NewNil(), nil
+				}
+				if _, err := args[0].apply(firsts); err != nil {
+					return nil, err
 				}
 			}
-			return NewNil(), nil
 		},
 	},
 
 	Primitive{"filter", 2, 2,
 		func(name string, args []Value) (Value, error) {
+			// the result is of the same kind as the sequence filtered
 			if err := checkArgType(name, args[0], isFunction); err != nil {
 				return nil, err
 			}
-			if err := checkArgType(name, args[1], isList); err != nil {
+			if anyLazy(args[1:]) {
+				next, err := seqArg(name, args[1])
+				if err != nil {
+					return nil, err
+				}
+				return lazyFilter(args[0], next), nil
+			}
+			items, err := seqToSlice(name, args[1])
+			if err != nil {
 				return nil, err
 			}
-			var result Value = nil
-			var current_result MutableCons = nil
-			current := args[1]
-			for head, next, ok := args[1].asCons(); ok; head, next, ok = next.asCons() {
-				v, err := args[0].apply([]Value{head})
+			results := []Value{}
+			for _, item := range items {
+				v, err := applyFirst(args[0], []Value{item})
 				if err != nil {
 					return nil, err
 				}
 				if v.isTrue() {
-					cell := NewMutableCons(head, nil)
-					if current_result == nil {
-						result = cell
-					} else {
-						current_result.setTail(cell)
-					}
-					current_result = cell
+					results = append(results, item)
 				}
-				current = next
 			}
-			if !current.isEmpty() {
-				return nil, fmt.Errorf("%s - malformed list", name)
-			}
-			if current_result == nil {
-				return NewEmpty(), nil
-			}
-			current_result.setTail(NewEmpty())
-			return result, nil
+			return sameKind(args[1], results)
 		},
 	},
 
@@ -580,32 +1090,17 @@ var CORE_PRIMITIVES = []Primitive{
 			if err := checkArgType(name, args[0], isFunction); err != nil {
 				return nil, err
 			}
-			if err := checkArgType(name, args[1], isList); err != nil {
+			items, err := seqToSlice(name, args[1])
+			if err != nil {
 				return nil, err
 			}
-			var temp Value = NewEmpty()
-			// first reverse the list
-			current := args[1]
-			for head, next, ok := args[1].asCons(); ok; head, next, ok = next.asCons() {
-				temp = NewCons(head, temp)
-				current = next
-			}
-			if !current.isEmpty() {
-				return nil, fmt.Errorf("%s - malformed list", name)
-			}
-			// then fold it
 			result := args[2]
-			current = temp
-			for head, next, ok := temp.asCons(); ok; head, next, ok = next.asCons() {
-				v, err := args[0].apply([]Value{head, result})
+			for i := len(items) - 1; i >= 0; i-- {
+				v, err := applyFirst(args[0], []Value{items[i], result})
 				if err != nil {
 					return nil, err
 				}
 				result = v
-				current = next
-			}
-			if !current.isEmpty() {
-				return nil, fmt.Errorf("%s - malformed list", name)
 			}
 			return result, nil
 		},
@@ -616,23 +1111,63 @@ var CORE_PRIMITIVES = []Primitive{
 			if err := checkArgType(name, args[0], isFunction); err != nil {
 				return nil, err
 			}
-			if err := checkArgType(name, args[1], isList); err != nil {
+			next, err := seqArg(name, args[1])
+			if err != nil {
 				return nil, err
 			}
 			result := args[2]
-			current := args[1]
-			for head, next, ok := args[1].asCons(); ok; head, next, ok = next.asCons() {
-				v, err := args[0].apply([]Value{result, head})
+			for {
+				item, ok, err := next()
+				if err != nil {
+					return nil, err
+				}
+				if !ok {
+					return result, nil
+				}
+				v, err := applyFirst(args[0], []Value{result, item})
 				if err != nil {
 					return nil, err
 				}
 				result = v
-				current = next
 			}
-			if !current.isEmpty() {
-				return nil, fmt.Errorf("%s - malformed list", name)
+		},
+	},
+
+	Primitive{"->list", 1, 1,
+		func(name string, args []Value) (Value, error) {
+			items, err := seqToSlice(name, args[0])
+			if err != nil {
+				return nil, err
 			}
-			return result, nil
+			return listFromSlice(items), nil
+		},
+	},
+
+	Primitive{"sequence?", 1, 1,
+		func(name string, args []Value) (Value, error) {
+			return NewBoolean(isSequence(args[0])), nil
+		},
+	},
+
+	Primitive{"make-iterable", 1, 1,
+		func(name string, args []Value) (Value, error) {
+			// (make-iterable f) where f returns a fresh generator function
+			// each time the iterable is iterated over; the generator returns
+			// the next element on each call, and eof when done
+			if err := checkArgType(name, args[0], isFunction); err != nil {
+				return nil, err
+			}
+			f := args[0]
+			return NewIterable("iterable", func() (seqIterator, error) {
+				return iterableFromFunction(name, f)
+			}), nil
+		},
+	},
+
+	Primitive{"iterable?", 1, 1,
+		func(name string, args []Value) (Value, error) {
+			_, ok := args[0].(*vIterable)
+			return NewBoolean(ok), nil
 		},
 	},
 
@@ -671,15 +1206,21 @@ var CORE_PRIMITIVES = []Primitive{
 		},
 	},
 
+	Primitive{"pair?", 1, 1,
+		func(name string, args []Value) (Value, error) {
+			_, _, ok := args[0].asCons()
+			return NewBoolean(ok), nil
+		},
+	},
+
 	Primitive{"list?", 1, 1,
 		func(name string, args []Value) (Value, error) {
-			result := false
-			if _, _, ok := args[0].asCons(); ok {
-				result = true
-			} else {
-				result = args[0].isEmpty()
+			// only proper lists, ending with the empty list
+			current := args[0]
+			for _, next, ok := current.asCons(); ok; _, next, ok = next.asCons() {
+				current = next
 			}
-			return NewBoolean(result), nil
+			return NewBoolean(current.isEmpty()), nil
 		},
 	},
 
@@ -711,6 +1252,13 @@ var CORE_PRIMITIVES = []Primitive{
 		},
 	},
 
+	Primitive{"char?", 1, 1,
+		func(name string, args []Value) (Value, error) {
+			_, ok := args[0].asChar()
+			return NewBoolean(ok), nil
+		},
+	},
+
 	Primitive{"symbol?", 1, 1,
 		func(name string, args []Value) (Value, error) {
 			_, ok := args[0].asSymbol()
@@ -718,6 +1266,13 @@ var CORE_PRIMITIVES = []Primitive{
 		},
 	},
 
+	Primitive{"keyword?", 1, 1,
+		func(name string, args []Value) (Value, error) {
+			_, ok := args[0].asKeyword()
+			return NewBoolean(ok), nil
+		},
+	},
+
 	Primitive{"function?", 1, 1,
 		func(name string, args []Value) (Value, error) {
 			return NewBoolean(args[0].isFunction()), nil
@@ -749,7 +1304,7 @@ var CORE_PRIMITIVES = []Primitive{
 
 	Primitive{"dict", 0, -1,
 		func(name string, args []Value) (Value, error) {
-			content := make(map[string]Value, len(args))
+			content := newDictMap()
 			for _, v := range args {
 				head, tail, ok := v.asCons()
 				if !ok {
@@ -759,11 +1314,9 @@ var CORE_PRIMITIVES = []Primitive{
 				if !ok || !tail.isEmpty() { 
 					return nil, fmt.Errorf("dict item not a pair - %s", v.Display())
 				}
-				name, ok := head.asSymbol()
-				if !ok {
-					return nil, fmt.Errorf("dict key is not a symbol - %s", head.Display())
+				if err := content.set(head, head2); err != nil {
+					return nil, fmt.Errorf("%s - %s", name, err.Error())
 				}
-				content[name] = head2
 			}
 			return NewDict(content), nil
 		},
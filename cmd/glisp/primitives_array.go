@@ -0,0 +1,186 @@
+package main
+
+import "fmt"
+
+func arrayArg(name string, args []Value, i int) (*vArray, error) {
+	arr, ok := args[i].(*vArray)
+	if err := checkArgTypeB(name, args[i], ok); err != nil {
+		return nil, err
+	}
+	return arr, nil
+}
+
+func indexArg(name string, args []Value, i int, bound int) (int, error) {
+	// index in [0, bound]
+	idx, ok := args[i].asInteger()
+	if err := checkArgTypeB(name, args[i], ok); err != nil {
+		return 0, err
+	}
+	if idx < 0 || idx > bound {
+		return 0, fmt.Errorf("%s - index %d out of bound", name, idx)
+	}
+	return idx, nil
+}
+
+var ARRAY_PRIMITIVES = []Primitive{
+
+	Primitive{"array-length", 1, 1,
+		func(name string, args []Value) (Value, error) {
+			arr, err := arrayArg(name, args, 0)
+			if err != nil {
+				return nil, err
+			}
+			return NewInteger(len(arr.content)), nil
+		},
+	},
+
+	Primitive{"make-array", 1, 2,
+		func(name string, args []Value) (Value, error) {
+			n, err := countArgN(name, args[0])
+			if err != nil {
+				return nil, err
+			}
+			var fill Value = NewNil()
+			if len(args) > 1 {
+				fill = args[1]
+			}
+			content := make([]Value, n)
+			for i := range content {
+				content[i] = fill
+			}
+			return NewArray(content), nil
+		},
+	},
+
+	Primitive{"array-fill!", 2, 2,
+		func(name string, args []Value) (Value, error) {
+			arr, err := arrayArg(name, args, 0)
+			if err != nil {
+				return nil, err
+			}
+			for i := range arr.content {
+				arr.content[i] = args[1]
+			}
+			return NewNil(), nil
+		},
+	},
+
+	Primitive{"array-push", 2, -1,
+		func(name string, args []Value) (Value, error) {
+			arr, err := arrayArg(name, args, 0)
+			if err != nil {
+				return nil, err
+			}
+			arr.content = append(arr.content, args[1:]...)
+			return NewNil(), nil
+		},
+	},
+
+	Primitive{"array-pop", 1, 1,
+		func(name string, args []Value) (Value, error) {
+			arr, err := arrayArg(name, args, 0)
+			if err != nil {
+				return nil, err
+			}
+			if len(arr.content) == 0 {
+				return nil, fmt.Errorf("%s - empty array argument", name)
+			}
+			last := arr.content[len(arr.content)-1]
+			arr.content[len(arr.content)-1] = nil
+			arr.content = arr.content[:len(arr.content)-1]
+			return last, nil
+		},
+	},
+
+	Primitive{"array-slice", 2, 3,
+		func(name string, args []Value) (Value, error) {
+			arr, err := arrayArg(name, args, 0)
+			if err != nil {
+				return nil, err
+			}
+			start, err := indexArg(name, args, 1, len(arr.content))
+			if err != nil {
+				return nil, err
+			}
+			end := len(arr.content)
+			if len(args) > 2 {
+				end, err = indexArg(name, args, 2, len(arr.content))
+				if err != nil {
+					return nil, err
+				}
+			}
+			if end < start {
+				return nil, fmt.Errorf("%s - end %d before start %d", name, end, start)
+			}
+			content := make([]Value, end-start)
+			copy(content, arr.content[start:end])
+			return NewArray(content), nil
+		},
+	},
+
+	Primitive{"array-copy", 1, 1,
+		func(name string, args []Value) (Value, error) {
+			arr, err := arrayArg(name, args, 0)
+			if err != nil {
+				return nil, err
+			}
+			content := make([]Value, len(arr.content))
+			copy(content, arr.content)
+			return NewArray(content), nil
+		},
+	},
+
+	Primitive{"array->list", 1, 1,
+		func(name string, args []Value) (Value, error) {
+			arr, err := arrayArg(name, args, 0)
+			if err != nil {
+				return nil, err
+			}
+			return listFromSlice(arr.content), nil
+		},
+	},
+
+	Primitive{"list->array", 1, 1,
+		func(name string, args []Value) (Value, error) {
+			items, err := listToSlice(name, args[0])
+			if err != nil {
+				return nil, err
+			}
+			return NewArray(items), nil
+		},
+	},
+
+	Primitive{"array-sort!", 1, 2,
+		func(name string, args []Value) (Value, error) {
+			arr, err := arrayArg(name, args, 0)
+			if err != nil {
+				return nil, err
+			}
+			if err := sortValues(name, arr.content, args, 1); err != nil {
+				return nil, err
+			}
+			return NewNil(), nil
+		},
+	},
+
+	Primitive{"array-map", 2, 2,
+		func(name string, args []Value) (Value, error) {
+			if err := checkArgType(name, args[0], isFunction); err != nil {
+				return nil, err
+			}
+			arr, err := arrayArg(name, args, 1)
+			if err != nil {
+				return nil, err
+			}
+			content := make([]Value, len(arr.content))
+			for i, item := range arr.content {
+				v, err := applyFirst(args[0], []Value{item})
+				if err != nil {
+					return nil, err
+				}
+				content[i] = v
+			}
+			return NewArray(content), nil
+		},
+	},
+}
@@ -0,0 +1,21 @@
+package main
+
+import "testing"
+
+func TestArrayPrimitives(t *testing.T) {
+	checkEval(t, `(array-length (array 1 2 3))`, `3`)
+	checkEval(t, `(make-array 2 0)`, `#[0 0]`)
+	checkEval(t, `(let ((a (make-array 3))) (do (array-fill! a 7) a))`, `#[7 7 7]`)
+	checkEval(t, `(let ((a (array 1))) (do (array-push a 2 3) a))`, `#[1 2 3]`)
+	checkEval(t, `(let ((a (array 1 2))) (list (array-pop a) a))`, `(2 #[1])`)
+	checkEval(t, `(array-slice (array 1 2 3 4) 1 3)`, `#[2 3]`)
+	checkEval(t, `(array-slice (array 1 2 3 4) 2)`, `#[3 4]`)
+	checkEval(t, `(def a (array 1 2)) (def b (array-copy a)) (array-push b 3) (list a b)`, `(#[1 2] #[1 2 3])`)
+	checkEval(t, `(array->list (array 1 2))`, `(1 2)`)
+	checkEval(t, `(list->array '(1 2))`, `#[1 2]`)
+	checkEval(t, `(let ((a (array 3 1 2))) (do (array-sort! a) a))`, `#[1 2 3]`)
+	checkEval(t, `(array-map (fn (x) (* x x)) (array 1 2 3))`, `#[1 4 9]`)
+	checkEvalError(t, `(array-pop (array))`, "array-pop - empty array argument")
+	checkEvalError(t, `(array-slice (array 1 2) 3)`, "array-slice - index 3 out of bound")
+	checkEvalError(t, `(array-slice (array 1 2 3) 2 1)`, "array-slice - end 1 before start 2")
+}
@@ -0,0 +1,138 @@
+package main
+
+import "errors"
+import "io"
+import "os/exec"
+import "strings"
+
+// Commands run in the engine's current directory with the engine's
+// environment, including variables overridden by with-env.
+
+func commandArgs(name string, args []Value) ([]string, error) {
+	argv := make([]string, len(args))
+	for i, arg := range args {
+		str, ok := arg.asString()
+		if err := checkArgTypeB(name, arg, ok); err != nil {
+			return nil, err
+		}
+		argv[i] = str
+	}
+	return argv, nil
+}
+
+func (st *engineState) command(argv []string) *exec.Cmd {
+	cmd := exec.Command(argv[0], argv[1:]...)
+	cmd.Dir = st.dir
+	cmd.Env = st.environList()
+	return cmd
+}
+
+type commandCloser struct {
+	pipe io.Closer
+	cmd  *exec.Cmd
+}
+
+func (c *commandCloser) Close() error {
+	// a command that exits with a failure status is not an error here
+	c.pipe.Close()
+	err := c.cmd.Wait()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return nil
+	}
+	return err
+}
+
+func (st *engineState) openCommandPort(argv []string, mode string) (*vPort, error) {
+	cmd := st.command(argv)
+	cmd.Stderr = st.stderr.writer
+	name := strings.Join(argv, " ")
+	if mode == "read" {
+		out, err := cmd.StdoutPipe()
+		if err != nil {
+			return nil, err
+		}
+		if err := cmd.Start(); err != nil {
+			return nil, err
+		}
+		return NewInputPort(name, out, &commandCloser{out, cmd}), nil
+	}
+	cmd.Stdout = st.stdout.writer
+	in, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	return NewOutputPort(name, in, &commandCloser{in, cmd}), nil
+}
+
+func commandPrimitives(st *engineState) []Primitive {
+	return []Primitive{
+
+		Primitive{"run", 1, -1,
+			func(name string, args []Value) (Value, error) {
+				// (run cmd arg ...) waits for the command and returns its
+				// exit status; it uses the engine's stdin, stdout and stderr
+				argv, err := commandArgs(name, args)
+				if err != nil {
+					return nil, err
+				}
+				stdin, err := st.stdin.input()
+				if err != nil {
+					return nil, portError(name, err)
+				}
+				if err := st.stdout.checkOutput(); err != nil {
+					return nil, portError(name, err)
+				}
+				if err := st.stderr.checkOutput(); err != nil {
+					return nil, portError(name, err)
+				}
+				cmd := st.command(argv)
+				cmd.Stdin = stdin
+				cmd.Stdout = st.stdout.writer
+				cmd.Stderr = st.stderr.writer
+				err = cmd.Run()
+				var exitErr *exec.ExitError
+				if errors.As(err, &exitErr) {
+					return NewInteger(exitErr.ExitCode()), nil
+				}
+				if err != nil {
+					return nil, portError(name, err)
+				}
+				return NewInteger(0), nil
+			},
+		},
+
+		Primitive{"open-input-command", 1, -1,
+			func(name string, args []Value) (Value, error) {
+				// a port reading the output of cmd; closing it waits for cmd
+				argv, err := commandArgs(name, args)
+				if err != nil {
+					return nil, err
+				}
+				p, err := st.openCommandPort(argv, "read")
+				if err != nil {
+					return nil, portError(name, err)
+				}
+				return p, nil
+			},
+		},
+
+		Primitive{"open-output-command", 1, -1,
+			func(name string, args []Value) (Value, error) {
+				// a port writing to the input of cmd; closing it waits for cmd
+				argv, err := commandArgs(name, args)
+				if err != nil {
+					return nil, err
+				}
+				p, err := st.openCommandPort(argv, "write")
+				if err != nil {
+					return nil, portError(name, err)
+				}
+				return p, nil
+			},
+		},
+	}
+}
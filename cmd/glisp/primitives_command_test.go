@@ -0,0 +1,32 @@
+package main
+
+import "fmt"
+import "path/filepath"
+import "strings"
+import "testing"
+
+func TestRunUsesEngineEnv(t *testing.T) {
+	checkEval(t, `(with-env ((FOO "bar")) (run "sh" "-c" "test $FOO = bar"))`, `0`)
+	checkEval(t, `(run "sh" "-c" "exit 3")`, `3`)
+	checkEvalError(t, `(run "sh" 1)`, `run`)
+}
+
+func TestCommandPorts(t *testing.T) {
+	checkEval(t, `(with-env ((FOO "bar")) (let ((p (open-input-command "sh" "-c" "echo $FOO; echo baz"))) (let ((line (read-line p))) (do (close p) line))))`, `"bar"`)
+	out := filepath.Join(t.TempDir(), "out")
+	checkEval(t, fmt.Sprintf(`(with-env ((OUT %q)) (let ((p (open-output-command "sh" "-c" "cat > $OUT"))) (do (write-line "hello" p) (close p) (let ((q (open-input-file %q))) (read-line q)))))`, out, out), `"hello"`)
+}
+
+func TestRunUsesEnginePorts(t *testing.T) {
+	var out strings.Builder
+	e := NewEngine()
+	e.state.stdin = NewInputPort("test", strings.NewReader("hello\nworld\n"), nil)
+	e.state.stdout = NewOutputPort("test", &out, nil)
+	v, err := evalSource(e, `(list (read-line) (run "sh" "-c" "read x; echo $x"))`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Display() != `("hello" 0)` || out.String() != "world\n" {
+		t.Errorf("expected the command to read and write the engine's ports but got %s and %q", v.Display(), out.String())
+	}
+}
@@ -0,0 +1,166 @@
+package main
+
+import "fmt"
+
+func dictArg(name string, args []Value, i int) (*vDict, error) {
+	d, ok := args[i].(*vDict)
+	if err := checkArgTypeB(name, args[i], ok); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func dictKeyArgs(name string, args []Value) (*vDict, Value, error) {
+	d, err := dictArg(name, args, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, ok := hashKey(args[1]); !ok {
+		return nil, nil, fmt.Errorf("%s - %s", name, dictKeyError(args[1]).Error())
+	}
+	return d, args[1], nil
+}
+
+var DICT_PRIMITIVES = []Primitive{
+
+	Primitive{"dict-keys", 1, 1,
+		func(name string, args []Value) (Value, error) {
+			d, err := dictArg(name, args, 0)
+			if err != nil {
+				return nil, err
+			}
+			return listFromSlice(d.content.keys()), nil
+		},
+	},
+
+	Primitive{"dict-values", 1, 1,
+		func(name string, args []Value) (Value, error) {
+			d, err := dictArg(name, args, 0)
+			if err != nil {
+				return nil, err
+			}
+			return listFromSlice(d.content.values()), nil
+		},
+	},
+
+	Primitive{"dict-has?", 2, 2,
+		func(name string, args []Value) (Value, error) {
+			d, key, err := dictKeyArgs(name, args)
+			if err != nil {
+				return nil, err
+			}
+			_, ok, _ := d.content.get(key)
+			return NewBoolean(ok), nil
+		},
+	},
+
+	Primitive{"dict-get", 2, 3,
+		func(name string, args []Value) (Value, error) {
+			// missing keys give the default, or #f without one
+			d, key, err := dictKeyArgs(name, args)
+			if err != nil {
+				return nil, err
+			}
+			if v, ok, _ := d.content.get(key); ok {
+				return v, nil
+			}
+			if len(args) > 2 {
+				return args[2], nil
+			}
+			return NewBoolean(false), nil
+		},
+	},
+
+	Primitive{"dict-delete", 2, 2,
+		func(name string, args []Value) (Value, error) {
+			d, key, err := dictKeyArgs(name, args)
+			if err != nil {
+				return nil, err
+			}
+			d.content.remove(key)
+			return NewNil(), nil
+		},
+	},
+
+	Primitive{"dict-merge", 1, -1,
+		func(name string, args []Value) (Value, error) {
+			// a new dict, with later dicts taking precedence
+			content := newDictMap()
+			for i := range args {
+				d, err := dictArg(name, args, i)
+				if err != nil {
+					return nil, err
+				}
+				for _, entry := range d.content.entries() {
+					content.set(entry.key, entry.value)
+				}
+			}
+			return NewDict(content), nil
+		},
+	},
+
+	Primitive{"dict->list", 1, 1,
+		func(name string, args []Value) (Value, error) {
+			d, err := dictArg(name, args, 0)
+			if err != nil {
+				return nil, err
+			}
+			items, err := seqToSlice(name, d)
+			if err != nil {
+				return nil, err
+			}
+			return listFromSlice(items), nil
+		},
+	},
+
+	Primitive{"dict-update", 3, 4,
+		func(name string, args []Value) (Value, error) {
+			// (dict-update d key f [default]) sets key to (f value),
+			// using default when key is missing
+			d, key, err := dictKeyArgs(name, args)
+			if err != nil {
+				return nil, err
+			}
+			if err := checkArgType(name, args[2], isFunction); err != nil {
+				return nil, err
+			}
+			current, ok, _ := d.content.get(key)
+			if !ok {
+				if len(args) < 4 {
+					return nil, fmt.Errorf("%s - key %s not in dict", name, key.Display())
+				}
+				current = args[3]
+			}
+			v, err := applyFirst(args[2], []Value{current})
+			if err != nil {
+				return nil, err
+			}
+			d.content.set(key, v)
+			return NewNil(), nil
+		},
+	},
+
+	Primitive{"dict-for-each", 2, 2,
+		func(name string, args []Value) (Value, error) {
+			// f is called with each key and value
+			if err := checkArgType(name, args[0], isFunction); err != nil {
+				return nil, err
+			}
+			d, err := dictArg(name, args, 1)
+			if err != nil {
+				return nil, err
+			}
+			for _, k := range d.content.keys() {
+				v, ok, _ := d.content.get(k)
+				if !ok {
+					// deleted during iteration
+					continue
+				}
+				if _, err := args[0].apply([]Value{k, v}); err != nil {
+					return nil, err
+				}
+			}
+			return NewNil(), nil
+		},
+	},
+}
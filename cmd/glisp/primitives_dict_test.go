@@ -0,0 +1,22 @@
+package main
+
+import "testing"
+
+func TestDictPrimitives(t *testing.T) {
+	checkEval(t, `(dict-keys (dict '(b 2) '(a 1)))`, `(b a)`)
+	checkEval(t, `(dict-values (dict '(b 2) '(a 1)))`, `(2 1)`)
+	checkEval(t, `(list (dict-has? (dict '(a 1)) 'a) (dict-has? (dict '(a 1)) 'b))`, `(#t #f)`)
+	checkEval(t, `(list (dict-get (dict '(a 1)) 'a) (dict-get (dict '(a 1)) 'b 0))`, `(1 0)`)
+	checkEval(t, `(let ((d (dict '(a 1) '(b 2)))) (do (dict-delete d 'a) (dict-keys d)))`, `(b)`)
+	checkEval(t, `(dict->list (dict-merge (dict '(a 1) '(b 2)) (dict '(b 3))))`, `((a 1) (b 3))`)
+	checkEval(t, `(let ((d (dict '(a 1)))) (do (dict-update d 'a inc) (dict-update d 'b inc 10) (dict->list d)))`, `((a 2) (b 11))`)
+	checkEval(t, `(let ((r (ref '()))) (do (dict-for-each (fn (k v) (r (cons k (r)))) (dict '(a 1) '(b 2))) (r)))`, `(b a)`)
+	checkEvalError(t, `(dict-update (dict) 'a inc)`, "dict-update - key a not in dict")
+	checkEvalError(t, `(dict-get (dict) (array))`, "dict-get - key not hashable #[]")
+}
+
+func TestDictKeys(t *testing.T) {
+	checkEval(t, `(dict-get (dict (list 1 'one) (list "two" 2) (list '(3 4) 'pair)) '(3 4))`, `pair`)
+	checkEval(t, `(dict-get (dict (list #\a 1) (list #t 2)) #t)`, `2`)
+	checkEval(t, `(dict-keys (dict '(c 1) '(a 2) '(b 3)))`, `(c a b)`)
+}
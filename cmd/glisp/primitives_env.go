@@ -0,0 +1,128 @@
+package main
+
+import "fmt"
+import "sort"
+
+func envNameArg(name string, args []Value, i int) (string, error) {
+	// variable names can be given as strings or symbols
+	if str, ok := args[i].asString(); ok {
+		return str, nil
+	}
+	sym, ok := args[i].asSymbol()
+	if err := checkArgTypeB(name, args[i], ok); err != nil {
+		return "", err
+	}
+	return sym, nil
+}
+
+func envPrimitives(st *engineState) []Primitive {
+	return []Primitive{
+
+		Primitive{"getenv", 1, 1,
+			func(name string, args []Value) (Value, error) {
+				key, err := envNameArg(name, args, 0)
+				if err != nil {
+					return nil, err
+				}
+				value, ok := st.environ[key]
+				if !ok {
+					return NewBoolean(false), nil
+				}
+				return NewString(value), nil
+			},
+		},
+
+		Primitive{"setenv", 2, 2,
+			func(name string, args []Value) (Value, error) {
+				key, err := envNameArg(name, args, 0)
+				if err != nil {
+					return nil, err
+				}
+				value, ok := args[1].asString()
+				if err := checkArgTypeB(name, args[1], ok); err != nil {
+					return nil, err
+				}
+				st.environ[key] = value
+				return NewNil(), nil
+			},
+		},
+
+		Primitive{"unsetenv", 1, 1,
+			func(name string, args []Value) (Value, error) {
+				key, err := envNameArg(name, args, 0)
+				if err != nil {
+					return nil, err
+				}
+				delete(st.environ, key)
+				return NewNil(), nil
+			},
+		},
+
+		Primitive{"environ", 0, 0,
+			func(name string, args []Value) (Value, error) {
+				// keyed by variable name, in sorted order
+				names := make([]string, 0, len(st.environ))
+				for k := range st.environ {
+					names = append(names, k)
+				}
+				sort.Strings(names)
+				values := make([]Value, len(names))
+				for i, k := range names {
+					values[i] = NewString(st.environ[k])
+				}
+				return symbolDict(names, values), nil
+			},
+		},
+
+		Primitive{"call-with-env", 1, -1,
+			func(name string, args []Value) (Value, error) {
+				// (call-with-env thunk name1 value1 name2 value2 ...)
+				// a value of #f unsets the variable for the extent of the call
+				if err := checkArgType(name, args[0], isFunction); err != nil {
+					return nil, err
+				}
+				if len(args)%2 != 1 {
+					return nil, fmt.Errorf("%s - odd number of name/value arguments", name)
+				}
+				keys := []string{}
+				values := []Value{}
+				for i := 1; i < len(args); i += 2 {
+					key, err := envNameArg(name, args, i)
+					if err != nil {
+						return nil, err
+					}
+					if _, ok := args[i+1].asString(); !ok && args[i+1].isTrue() {
+						return nil, fmt.Errorf("%s - wrong argument type %s", name, args[i+1].typ())
+					}
+					keys = append(keys, key)
+					values = append(values, args[i+1])
+				}
+				saved := make(map[string]string, len(keys))
+				missing := map[string]bool{}
+				for _, key := range keys {
+					if old, ok := st.environ[key]; ok {
+						saved[key] = old
+					} else {
+						missing[key] = true
+					}
+				}
+				defer func() {
+					for key, old := range saved {
+						st.environ[key] = old
+					}
+					for key := range missing {
+						delete(st.environ, key)
+					}
+				}()
+				for i, key := range keys {
+					if value, ok := values[i].asString(); ok {
+						st.environ[key] = value
+					} else {
+						delete(st.environ, key)
+					}
+				}
+				return args[0].apply([]Value{})
+			},
+		},
+	}
+}
@@ -0,0 +1,20 @@
+package main
+
+import "testing"
+
+func TestEnvPrimitives(t *testing.T) {
+	checkEval(t, `(do (setenv "GLISP_TEST" "a") (getenv 'GLISP_TEST))`, `"a"`)
+	checkEval(t, `(do (setenv "GLISP_TEST" "a") (unsetenv "GLISP_TEST") (getenv "GLISP_TEST"))`, `#f`)
+	checkEval(t, `(do (setenv "GLISP_TEST" "a") (dict? (environ)))`, `#t`)
+	// engines do not share their environment
+	checkEval(t, `(getenv "GLISP_TEST")`, `#f`)
+	checkEvalError(t, `(setenv "GLISP_TEST" 1)`, "setenv - wrong argument type")
+}
+
+func TestWithEnv(t *testing.T) {
+	checkEval(t, `(with-env ((GLISP_TEST "a") (GLISP_OTHER "b")) (list (getenv "GLISP_TEST") (getenv "GLISP_OTHER")))`, `("a" "b")`)
+	checkEval(t, `(do (setenv "GLISP_TEST" "a") (with-env ((GLISP_TEST "b")) 1) (getenv "GLISP_TEST"))`, `"a"`)
+	checkEval(t, `(do (with-env ((GLISP_TEST "b")) 1) (getenv "GLISP_TEST"))`, `#f`)
+	checkEval(t, `(do (setenv "GLISP_TEST" "a") (with-env ((GLISP_TEST #f)) (getenv "GLISP_TEST")))`, `#f`)
+	checkEval(t, `(let ((call-with-env 5)) (with-env ((FOO "x")) (getenv "FOO")))`, `"x"`)
+}
@@ -0,0 +1,391 @@
+package main
+
+import "fmt"
+import "io/ioutil"
+import "os"
+import "path/filepath"
+import "sort"
+import "strings"
+
+func pathArg(name string, args []Value, i int) (string, error) {
+	path, ok := args[i].asString()
+	if err := checkArgTypeB(name, args[i], ok); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+func mkPathFunction(f func(string) string) func(string, []Value) (Value, error) {
+	return func(name string, args []Value) (Value, error) {
+		path, err := pathArg(name, args, 0)
+		if err != nil {
+			return nil, err
+		}
+		return NewString(f(path)), nil
+	}
+}
+
+func fsError(name string, err error) error {
+	return fmt.Errorf("%s - %s", name, err.Error())
+}
+
+func writeFile(st *engineState, name string, args []Value, flag int) (Value, error) {
+	path, err := st.pathArg(name, args, 0)
+	if err != nil {
+		return nil, err
+	}
+	str, ok := args[1].asString()
+	if err := checkArgTypeB(name, args[1], ok); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, flag|os.O_WRONLY|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fsError(name, err)
+	}
+	if _, err := f.WriteString(str); err != nil {
+		f.Close()
+		return nil, fsError(name, err)
+	}
+	if err := f.Close(); err != nil {
+		return nil, fsError(name, err)
+	}
+	return NewNil(), nil
+}
+
+func fileStat(info os.FileInfo) Value {
+	return symbolDict([]string{"name", "size", "mode", "mtime", "dir?"}, []Value{
+		NewString(info.Name()),
+		NewInteger(int(info.Size())),
+		NewString(info.Mode().String()),
+		NewInteger(int(info.ModTime().Unix())),
+		NewBoolean(info.IsDir()),
+	})
+}
+
+func fsPrimitives(st *engineState) []Primitive {
+	return []Primitive{
+
+		Primitive{"read-file", 1, 1,
+			func(name string, args []Value) (Value, error) {
+				path, err := st.pathArg(name, args, 0)
+				if err != nil {
+					return nil, err
+				}
+				content, err := ioutil.ReadFile(path)
+				if err != nil {
+					return nil, fsError(name, err)
+				}
+				return NewString(string(content)), nil
+			},
+		},
+
+		Primitive{"read-lines", 1, 1,
+			func(name string, args []Value) (Value, error) {
+				path, err := st.pathArg(name, args, 0)
+				if err != nil {
+					return nil, err
+				}
+				content, err := ioutil.ReadFile(path)
+				if err != nil {
+					return nil, fsError(name, err)
+				}
+				str := strings.TrimSuffix(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n")
+				if str == "" {
+					return NewEmpty(), nil
+				}
+				return stringsToList(strings.Split(str, "\n")), nil
+			},
+		},
+
+		Primitive{"write-file", 2, 2,
+			func(name string, args []Value) (Value, error) {
+				return writeFile(st, name, args, os.O_TRUNC)
+			},
+		},
+
+		Primitive{"append-file", 2, 2,
+			func(name string, args []Value) (Value, error) {
+				return writeFile(st, name, args, os.O_APPEND)
+			},
+		},
+
+		Primitive{"write-lines", 2, 2,
+			func(name string, args []Value) (Value, error) {
+				if err := checkArgType(name, args[1], isList); err != nil {
+					return nil, err
+				}
+				var b strings.Builder
+				current := args[1]
+				for head, next, ok := args[1].asCons(); ok; head, next, ok = next.asCons() {
+					str, ok := head.asString()
+					if err := checkArgTypeB(name, head, ok); err != nil {
+						return nil, err
+					}
+					b.WriteString(str)
+					b.WriteString("\n")
+					current = next
+				}
+				if !current.isEmpty() {
+					return nil, fmt.Errorf("%s - malformed list", name)
+				}
+				return writeFile(st, name, []Value{args[0], NewString(b.String())}, os.O_TRUNC)
+			},
+		},
+
+		Primitive{"list-dir", 0, 1,
+			func(name string, args []Value) (Value, error) {
+				path := st.dir
+				if len(args) > 0 {
+					p, err := st.pathArg(name, args, 0)
+					if err != nil {
+						return nil, err
+					}
+					path = p
+				}
+				infos, err := ioutil.ReadDir(path)
+				if err != nil {
+					return nil, fsError(name, err)
+				}
+				names := make([]string, len(infos))
+				for i, info := range infos {
+					names[i] = info.Name()
+				}
+				return stringsToList(names), nil
+			},
+		},
+
+		Primitive{"file-stat", 1, 1,
+			func(name string, args []Value) (Value, error) {
+				path, err := st.pathArg(name, args, 0)
+				if err != nil {
+					return nil, err
+				}
+				info, err := os.Stat(path)
+				if err != nil {
+					return nil, fsError(name, err)
+				}
+				return fileStat(info), nil
+			},
+		},
+
+		Primitive{"glob", 1, 1,
+			func(name string, args []Value) (Value, error) {
+				pattern, err := st.pathArg(name, args, 0)
+				if err != nil {
+					return nil, err
+				}
+				matches, err := filepath.Glob(pattern)
+				if err != nil {
+					return nil, fsError(name, err)
+				}
+				if raw, _ := args[0].asString(); !filepath.IsAbs(raw) && !isHomePath(raw) {
+					// report matches relative to the current directory
+					for i, m := range matches {
+						if rel, err := filepath.Rel(st.dir, m); err == nil {
+							matches[i] = rel
+						}
+					}
+				}
+				sort.Strings(matches)
+				return stringsToList(matches), nil
+			},
+		},
+
+		Primitive{"file-exists?", 1, 1,
+			func(name string, args []Value) (Value, error) {
+				path, err := st.pathArg(name, args, 0)
+				if err != nil {
+					return nil, err
+				}
+				_, err = os.Stat(path)
+				if err != nil && !os.IsNotExist(err) {
+					return nil, fsError(name, err)
+				}
+				return NewBoolean(err == nil), nil
+			},
+		},
+
+		Primitive{"file-directory?", 1, 1,
+			func(name string, args []Value) (Value, error) {
+				path, err := st.pathArg(name, args, 0)
+				if err != nil {
+					return nil, err
+				}
+				info, err := os.Stat(path)
+				if err != nil {
+					if os.IsNotExist(err) {
+						return NewBoolean(false), nil
+					}
+					return nil, fsError(name, err)
+				}
+				return NewBoolean(info.IsDir()), nil
+			},
+		},
+
+		Primitive{"make-dir", 1, 1,
+			func(name string, args []Value) (Value, error) {
+				path, err := st.pathArg(name, args, 0)
+				if err != nil {
+					return nil, err
+				}
+				if err := os.Mkdir(path, 0755); err != nil {
+					return nil, fsError(name, err)
+				}
+				return NewNil(), nil
+			},
+		},
+
+		Primitive{"make-dirs", 1, 1,
+			func(name string, args []Value) (Value, error) {
+				path, err := st.pathArg(name, args, 0)
+				if err != nil {
+					return nil, err
+				}
+				if err := os.MkdirAll(path, 0755); err != nil {
+					return nil, fsError(name, err)
+				}
+				return NewNil(), nil
+			},
+		},
+
+		Primitive{"remove-file", 1, 1,
+			func(name string, args []Value) (Value, error) {
+				// also removes empty directories
+				path, err := st.pathArg(name, args, 0)
+				if err != nil {
+					return nil, err
+				}
+				if err := os.Remove(path); err != nil {
+					return nil, fsError(name, err)
+				}
+				return NewNil(), nil
+			},
+		},
+
+		Primitive{"remove-all", 1, 1,
+			func(name string, args []Value) (Value, error) {
+				path, err := st.pathArg(name, args, 0)
+				if err != nil {
+					return nil, err
+				}
+				if err := os.RemoveAll(path); err != nil {
+					return nil, fsError(name, err)
+				}
+				return NewNil(), nil
+			},
+		},
+
+		Primitive{"rename-file", 2, 2,
+			func(name string, args []Value) (Value, error) {
+				from, err := st.pathArg(name, args, 0)
+				if err != nil {
+					return nil, err
+				}
+				to, err := st.pathArg(name, args, 1)
+				if err != nil {
+					return nil, err
+				}
+				if err := os.Rename(from, to); err != nil {
+					return nil, fsError(name, err)
+				}
+				return NewNil(), nil
+			},
+		},
+
+		Primitive{"path-join", 0, -1,
+			func(name string, args []Value) (Value, error) {
+				parts := make([]string, len(args))
+				for i := range args {
+					part, err := pathArg(name, args, i)
+					if err != nil {
+						return nil, err
+					}
+					parts[i] = part
+				}
+				return NewString(filepath.Join(parts...)), nil
+			},
+		},
+
+		Primitive{"path-base", 1, 1,
+			mkPathFunction(filepath.Base),
+		},
+
+		Primitive{"path-dir", 1, 1,
+			mkPathFunction(filepath.Dir),
+		},
+
+		Primitive{"path-ext", 1, 1,
+			mkPathFunction(filepath.Ext),
+		},
+
+		Primitive{"path-clean", 1, 1,
+			mkPathFunction(filepath.Clean),
+		},
+
+		Primitive{"path-abs", 1, 1,
+			func(name string, args []Value) (Value, error) {
+				path, err := st.pathArg(name, args, 0)
+				if err != nil {
+					return nil, err
+				}
+				return NewString(path), nil
+			},
+		},
+
+		Primitive{"path-rel", 2, 2,
+			func(name string, args []Value) (Value, error) {
+				base, err := st.pathArg(name, args, 0)
+				if err != nil {
+					return nil, err
+				}
+				target, err := st.pathArg(name, args, 1)
+				if err != nil {
+					return nil, err
+				}
+				rel, err := filepath.Rel(base, target)
+				if err != nil {
+					return nil, fsError(name, err)
+				}
+				return NewString(rel), nil
+			},
+		},
+
+		Primitive{"path-expand", 1, 1,
+			func(name string, args []Value) (Value, error) {
+				path, err := pathArg(name, args, 0)
+				if err != nil {
+					return nil, err
+				}
+				expanded, err := st.expandHome(path)
+				if err != nil {
+					return nil, fsError(name, err)
+				}
+				return NewString(expanded), nil
+			},
+		},
+
+		Primitive{"pwd", 0, 0,
+			func(name string, args []Value) (Value, error) {
+				return NewString(st.dir), nil
+			},
+		},
+
+		Primitive{"cd", 0, 1,
+			func(name string, args []Value) (Value, error) {
+				// without argument, go to the home directory
+				path := "~"
+				if len(args) > 0 {
+					p, err := pathArg(name, args, 0)
+					if err != nil {
+						return nil, err
+					}
+					path = p
+				}
+				if err := st.chdir(path); err != nil {
+					return nil, fsError(name, err)
+				}
+				return NewNil(), nil
+			},
+		},
+	}
+}
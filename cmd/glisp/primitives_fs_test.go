@@ -0,0 +1,50 @@
+package main
+
+import "fmt"
+import "io/ioutil"
+import "path/filepath"
+import "testing"
+
+func TestFilePrimitives(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "a.txt")
+	checkEval(t, fmt.Sprintf(`(do (write-file %q "hello") (append-file %q " world") (read-file %q))`, file, file, file), `"hello world"`)
+	checkEval(t, fmt.Sprintf(`(do (write-lines %q (list "a" "b")) (read-lines %q))`, file, file), `("a" "b")`)
+	checkEval(t, fmt.Sprintf(`(do (make-dirs %q) (make-dir %q) (list-dir %q))`, filepath.Join(dir, "b", "c"), filepath.Join(dir, "d"), dir), `("a.txt" "b" "d")`)
+	checkEval(t, fmt.Sprintf(`(list (file-exists? %q) (file-directory? %q) (file-directory? %q))`, file, file, dir), `(#t #f #t)`)
+	checkEval(t, fmt.Sprintf(`(do (rename-file %q %q) (glob %q))`, file, filepath.Join(dir, "e.txt"), filepath.Join(dir, "*.txt")), fmt.Sprintf(`(%q)`, filepath.Join(dir, "e.txt")))
+	checkEval(t, fmt.Sprintf(`(do (remove-file %q) (remove-all %q) (list-dir %q))`, filepath.Join(dir, "e.txt"), filepath.Join(dir, "b"), dir), `("d")`)
+	checkEval(t, fmt.Sprintf(`(file-exists? %q)`, file), `#f`)
+	checkEval(t, fmt.Sprintf(`(dict? (file-stat %q))`, dir), `#t`)
+	checkEvalError(t, fmt.Sprintf(`(read-file %q)`, file), "read-file - open")
+}
+
+func TestPathPrimitives(t *testing.T) {
+	checkEval(t, `(path-join "a" "b" "c.txt")`, `"a/b/c.txt"`)
+	checkEval(t, `(list (path-base "/a/b.txt") (path-dir "/a/b.txt") (path-ext "/a/b.txt"))`, `("b.txt" "/a" ".txt")`)
+	checkEval(t, `(path-clean "/a/./b/../c")`, `"/a/c"`)
+	checkEval(t, `(path-rel "/a" "/a/b/c")`, `"b/c"`)
+	checkEval(t, `(path-abs "/a/../b")`, `"/b"`)
+}
+
+func TestCurrentDirectory(t *testing.T) {
+	dir := t.TempDir()
+	if err := ioutil.WriteFile(filepath.Join(dir, "a.txt"), []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// relative paths are resolved against the engine's directory
+	checkEval(t, fmt.Sprintf(`(do (cd %q) (list (read-file "a.txt") (glob "*.txt") (path-abs "a.txt")))`, dir), fmt.Sprintf(`("hello" ("a.txt") %q)`, filepath.Join(dir, "a.txt")))
+	checkEval(t, fmt.Sprintf(`(do (cd %q) (pwd))`, dir), fmt.Sprintf(`%q`, dir))
+	checkEval(t, `(file-exists? "a.txt")`, `#f`)
+	checkEvalError(t, fmt.Sprintf(`(cd %q)`, filepath.Join(dir, "a.txt")), "is not a directory")
+}
+
+func TestGlobExpandsHome(t *testing.T) {
+	home := t.TempDir()
+	if err := ioutil.WriteFile(filepath.Join(home, "a.txt"), []byte{}, 0644); err != nil {
+		t.Fatal(err)
+	}
+	checkEval(t, fmt.Sprintf(`(with-env ((HOME %q)) (glob "~/*.txt"))`, home), fmt.Sprintf(`(%q)`, filepath.Join(home, "a.txt")))
+	checkEval(t, fmt.Sprintf(`(with-env ((HOME %q)) (path-expand "~/b"))`, home), fmt.Sprintf(`%q`, filepath.Join(home, "b")))
+	checkEval(t, `(glob "primitives_fs_test.go")`, `("primitives_fs_test.go")`)
+}
@@ -0,0 +1,157 @@
+package main
+
+func anyLazy(args []Value) bool {
+	for _, arg := range args {
+		if _, ok := arg.(*vLazySeq); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func lazyMap(f Value, nexts []seqIterator) Value {
+	return NewLazySeq(func() (Value, bool, error) {
+		firsts, ok, err := nextAll(nexts)
+		if err != nil || !ok {
+			return nil, false, err
+		}
+		v, err := applyFirst(f, firsts)
+		if err != nil {
+			return nil, false, err
+		}
+		return v, true, nil
+	})
+}
+
+func lazyFilter(pred Value, next seqIterator) Value {
+	return NewLazySeq(func() (Value, bool, error) {
+		for {
+			item, ok, err := next()
+			if err != nil || !ok {
+				return nil, false, err
+			}
+			keep, err := applyFirst(pred, []Value{item})
+			if err != nil {
+				return nil, false, err
+			}
+			if keep.isTrue() {
+				return item, true, nil
+			}
+		}
+	})
+}
+
+func lazyTake(n int, next seqIterator) Value {
+	// never pulls more than n elements from next
+	return NewLazySeq(func() (Value, bool, error) {
+		if n <= 0 {
+			return nil, false, nil
+		}
+		n--
+		return next()
+	})
+}
+
+func lazyDrop(n int, next seqIterator) Value {
+	return NewLazySeq(func() (Value, bool, error) {
+		for ; n > 0; n-- {
+			_, ok, err := next()
+			if err != nil || !ok {
+				return nil, false, err
+			}
+		}
+		return next()
+	})
+}
+
+var LAZY_PRIMITIVES = []Primitive{
+
+	Primitive{"make-promise", 1, 1,
+		func(name string, args []Value) (Value, error) {
+			// (delay expr) is (make-promise (fn () expr))
+			if err := checkArgType(name, args[0], isFunction); err != nil {
+				return nil, err
+			}
+			return NewPromise(args[0]), nil
+		},
+	},
+
+	Primitive{"force", 1, 1,
+		func(name string, args []Value) (Value, error) {
+			// anything but a promise forces to itself
+			p, ok := args[0].(*vPromise)
+			if !ok {
+				return args[0], nil
+			}
+			return p.force()
+		},
+	},
+
+	Primitive{"promise?", 1, 1,
+		func(name string, args []Value) (Value, error) {
+			_, ok := args[0].(*vPromise)
+			return NewBoolean(ok), nil
+		},
+	},
+
+	Primitive{"generator", 1, 1,
+		func(name string, args []Value) (Value, error) {
+			// a lazy sequence of the results of calling f until it returns eof
+			if err := checkArgType(name, args[0], isFunction); err != nil {
+				return nil, err
+			}
+			f := args[0]
+			return NewLazySeq(func() (Value, bool, error) {
+				v, err := applyFirst(f, []Value{})
+				if err != nil {
+					return nil, false, err
+				}
+				if _, ok := v.(*vEOF); ok {
+					return nil, false, nil
+				}
+				return v, true, nil
+			}), nil
+		},
+	},
+
+	Primitive{"iterate", 2, 2,
+		func(name string, args []Value) (Value, error) {
+			// the infinite sequence x, (f x), (f (f x)), ...
+			if err := checkArgType(name, args[0], isFunction); err != nil {
+				return nil, err
+			}
+			f, x := args[0], args[1]
+			var current Value
+			return NewLazySeq(func() (Value, bool, error) {
+				if current == nil {
+					current = x
+					return current, true, nil
+				}
+				v, err := applyFirst(f, []Value{current})
+				if err != nil {
+					return nil, false, err
+				}
+				current = v
+				return v, true, nil
+			}), nil
+		},
+	},
+
+	Primitive{"lazy-seq?", 1, 1,
+		func(name string, args []Value) (Value, error) {
+			_, ok := args[0].(*vLazySeq)
+			return NewBoolean(ok), nil
+		},
+	},
+
+	Primitive{"lazy", 1, 1,
+		func(name string, args []Value) (Value, error) {
+			// a lazy sequence over any sequence
+			next, err := seqArg(name, args[0])
+			if err != nil {
+				return nil, err
+			}
+			return NewLazySeq(next), nil
+		},
+	},
+}
@@ -0,0 +1,23 @@
+package main
+
+import "testing"
+
+func TestLazySeqsAreValues(t *testing.T) {
+	checkEval(t, `(def q (lazy (list 1 2 3))) (length q) (->list q)`, `(1 2 3)`)
+	checkEval(t, `(def s (map (fn (x) (* x x)) (iterate (fn (x) (+ x 1)) 0))) (->list (take 3 s)) (->list (take 3 s))`, `(0 1 4)`)
+	checkEval(t, `(def f (filter (fn (x) (> x 1)) (lazy (list 1 2 3)))) (->list f) (->list f)`, `(2 3)`)
+	checkEval(t, `(def n (ref 0)) (def s (map (fn (x) (do (n (+ (n) 1)) x)) (lazy (list 1 2 3)))) (->list s) (->list s) (n)`, `3`)
+	checkEval(t, `(->list (take 3 (drop 2 (iterate (fn (x) (* x 2)) 1))))`, `(4 8 16)`)
+}
+
+func TestLazyTakeDoesNotOverpull(t *testing.T) {
+	checkEval(t, `(def n (ref 0)) (def g (generator (fn () (do (n (+ (n) 1)) (n))))) (->list (take 2 g)) (n)`, `2`)
+}
+
+func TestDelayMemoizes(t *testing.T) {
+	checkEval(t, `(def n (ref 0)) (def p (delay (do (n (+ (n) 1)) 42))) (force p) (force p) (list (force p) (n))`, `(42 1)`)
+}
+
+func TestLines(t *testing.T) {
+	checkEval(t, `(with-open-file (p "primitives_lazy_test.go") (->list (take 2 (lines p))))`, `("package main" "")`)
+}
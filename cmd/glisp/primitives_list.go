@@ -0,0 +1,488 @@
+package main
+
+import "fmt"
+import "sort"
+
+func listToSlice(name string, v Value) ([]Value, error) {
+	if err := checkArgType(name, v, isList); err != nil {
+		return nil, err
+	}
+	result := []Value{}
+	current := v
+	for head, next, ok := v.asCons(); ok; head, next, ok = next.asCons() {
+		result = append(result, head)
+		current = next
+	}
+	if !current.isEmpty() {
+		return nil, fmt.Errorf("%s - malformed list", name)
+	}
+	return result, nil
+}
+
+func defaultLess(name string, v1 Value, v2 Value) (bool, error) {
+	// natural order on integers, strings and characters
+	if i1, ok := v1.asInteger(); ok {
+		if i2, ok := v2.asInteger(); ok {
+			return i1 < i2, nil
+		}
+	}
+	if s1, ok := v1.asString(); ok {
+		if s2, ok := v2.asString(); ok {
+			return s1 < s2, nil
+		}
+	}
+	if c1, ok := v1.asChar(); ok {
+		if c2, ok := v2.asChar(); ok {
+			return c1 < c2, nil
+		}
+	}
+	return false, fmt.Errorf("%s - cannot compare %s and %s", name, v1.typ(), v2.typ())
+}
+
+func sortValues(name string, vs []Value, args []Value, i int) error {
+	// stable sort, using the comparison function at position i if any
+	less := func(v1 Value, v2 Value) (bool, error) {
+		return defaultLess(name, v1, v2)
+	}
+	if len(args) > i {
+		if err := checkArgType(name, args[i], isFunction); err != nil {
+			return err
+		}
+		less = func(v1 Value, v2 Value) (bool, error) {
+			v, err := applyFirst(args[i], []Value{v1, v2})
+			if err != nil {
+				return false, err
+			}
+			return v.isTrue(), nil
+		}
+	}
+	var sortErr error
+	sort.SliceStable(vs, func(j, k int) bool {
+		if sortErr != nil {
+			return false
+		}
+		result, err := less(vs[j], vs[k])
+		if err != nil {
+			sortErr = err
+		}
+		return result
+	})
+	return sortErr
+}
+
+func countArgN(name string, arg Value) (int, error) {
+	n, ok := arg.asInteger()
+	if err := checkArgTypeB(name, arg, ok); err != nil {
+		return 0, err
+	}
+	if n < 0 {
+		return 0, fmt.Errorf("%s - negative count %d", name, n)
+	}
+	return n, nil
+}
+
+func rangeArgs(name string, args []Value) (int, int, int, error) {
+	bounds := make([]int, len(args))
+	for i, arg := range args {
+		n, ok := arg.asInteger()
+		if err := checkArgTypeB(name, arg, ok); err != nil {
+			return 0, 0, 0, err
+		}
+		bounds[i] = n
+	}
+	start, end, step := 0, bounds[0], 1
+	if len(bounds) > 1 {
+		start, end = bounds[0], bounds[1]
+	}
+	if len(bounds) > 2 {
+		step = bounds[2]
+	}
+	if step == 0 {
+		return 0, 0, 0, fmt.Errorf("%s - zero step", name)
+	}
+	return start, end, step, nil
+}
+
+func flatten(v Value, result []Value) []Value {
+	if _, _, ok := v.asCons(); !ok {
+		if v.isEmpty() {
+			return result
+		}
+		return append(result, v)
+	}
+	current := v
+	for head, next, ok := v.asCons(); ok; head, next, ok = next.asCons() {
+		result = flatten(head, result)
+		current = next
+	}
+	// the tail of a dotted pair is a leaf like any other
+	return flatten(current, result)
+}
+
+func mkListSearch(found func(bool, Value) (Value, bool), notFound Value) func(string, []Value) (Value, error) {
+	// apply a predicate to each element of a sequence in turn, stopping
+	// as soon as found() says so
+	return func(name string, args []Value) (Value, error) {
+		if err := checkArgType(name, args[0], isFunction); err != nil {
+			return nil, err
+		}
+		next, err := seqArg(name, args[1])
+		if err != nil {
+			return nil, err
+		}
+		for {
+			item, ok, err := next()
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				return notFound, nil
+			}
+			v, err := applyFirst(args[0], []Value{item})
+			if err != nil {
+				return nil, err
+			}
+			if result, ok := found(v.isTrue(), item); ok {
+				return result, nil
+			}
+		}
+	}
+}
+
+var LIST_PRIMITIVES = []Primitive{
+
+	Primitive{"sort", 1, 2,
+		func(name string, args []Value) (Value, error) {
+			items, err := seqToSlice(name, args[0])
+			if err != nil {
+				return nil, err
+			}
+			if err := sortValues(name, items, args, 1); err != nil {
+				return nil, err
+			}
+			return sameKind(args[0], items)
+		},
+	},
+
+	Primitive{"assoc", 2, -1,
+		func(name string, args []Value) (Value, error) {
+			// (assoc key alist) looks up an association list, while
+			// (assoc coll key value ...) updates a vector or hash-map;
+			// only the lookup takes two arguments, whatever the key
+			if len(args) > 2 {
+				return persistentAssoc(name, args)
+			}
+			items, err := listToSlice(name, args[1])
+			if err != nil {
+				return nil, err
+			}
+			for _, item := range items {
+				key, _, ok := item.asCons()
+				if !ok {
+					return nil, fmt.Errorf("%s - association list item not a pair %s", name, item.Display())
+				}
+				if key.isEqual(args[0]) {
+					return item, nil
+				}
+			}
+			return NewBoolean(false), nil
+		},
+	},
+
+	Primitive{"member", 2, 2,
+		func(name string, args []Value) (Value, error) {
+			if err := checkArgType(name, args[1], isList); err != nil {
+				return nil, err
+			}
+			current := args[1]
+			for head, next, ok := args[1].asCons(); ok; head, next, ok = next.asCons() {
+				if head.isEqual(args[0]) {
+					return current, nil
+				}
+				current = next
+			}
+			if !current.isEmpty() {
+				return nil, fmt.Errorf("%s - malformed list", name)
+			}
+			return NewBoolean(false), nil
+		},
+	},
+
+	Primitive{"range", 1, 3,
+		func(name string, args []Value) (Value, error) {
+			// (range end), (range start end) or (range start end step)
+			start, end, step, err := rangeArgs(name, args)
+			if err != nil {
+				return nil, err
+			}
+			items := []Value{}
+			for i := start; (step > 0 && i < end) || (step < 0 && i > end); i += step {
+				items = append(items, NewInteger(i))
+			}
+			return listFromSlice(items), nil
+		},
+	},
+
+	Primitive{"in-range", 1, 3,
+		func(name string, args []Value) (Value, error) {
+			// like range, but as an iterable producing integers on demand
+			start, end, step, err := rangeArgs(name, args)
+			if err != nil {
+				return nil, err
+			}
+			return rangeIterable(start, end, step), nil
+		},
+	},
+
+	Primitive{"take", 2, 2,
+		func(name string, args []Value) (Value, error) {
+			// lazy on a lazy sequence, pulling no more than n elements
+			n, err := countArgN(name, args[0])
+			if err != nil {
+				return nil, err
+			}
+			if anyLazy(args[1:]) {
+				next, err := seqArg(name, args[1])
+				if err != nil {
+					return nil, err
+				}
+				return lazyTake(n, next), nil
+			}
+			next, err := seqArg(name, args[1])
+			if err != nil {
+				return nil, err
+			}
+			items := []Value{}
+			for len(items) < n {
+				item, ok, err := next()
+				if err != nil {
+					return nil, err
+				}
+				if !ok {
+					break
+				}
+				items = append(items, item)
+			}
+			return sameKind(args[1], items)
+		},
+	},
+
+	Primitive{"drop", 2, 2,
+		func(name string, args []Value) (Value, error) {
+			n, err := countArgN(name, args[0])
+			if err != nil {
+				return nil, err
+			}
+			if anyLazy(args[1:]) {
+				next, err := seqArg(name, args[1])
+				if err != nil {
+					return nil, err
+				}
+				return lazyDrop(n, next), nil
+			}
+			if isList(args[1]) {
+				// share the rest of a list rather than copy it
+				current := args[1]
+				for ; n > 0; n-- {
+					_, next, ok := current.asCons()
+					if !ok {
+						break
+					}
+					current = next
+				}
+				if !isList(current) {
+					return nil, fmt.Errorf("%s - malformed list", name)
+				}
+				return current, nil
+			}
+			items, err := seqToSlice(name, args[1])
+			if err != nil {
+				return nil, err
+			}
+			return sameKind(args[1], items[min(n, len(items)):])
+		},
+	},
+
+	Primitive{"zip", 1, -1,
+		func(name string, args []Value) (Value, error) {
+			// stops with the shortest sequence
+			nexts, err := seqArgs(name, args)
+			if err != nil {
+				return nil, err
+			}
+			tuples := []Value{}
+			for {
+				tuple, ok, err := nextAll(nexts)
+				if err != nil {
+					return nil, err
+				}
+				if !ok {
+					return listFromSlice(tuples), nil
+				}
+				tuples = append(tuples, listFromSlice(tuple))
+			}
+		},
+	},
+
+	Primitive{"last", 1, 1,
+		func(name string, args []Value) (Value, error) {
+			next, err := seqArg(name, args[0])
+			if err != nil {
+				return nil, err
+			}
+			var last Value
+			for {
+				item, ok, err := next()
+				if err != nil {
+					return nil, err
+				}
+				if !ok {
+					break
+				}
+				last = item
+			}
+			if last == nil {
+				return nil, fmt.Errorf("%s - empty sequence argument", name)
+			}
+			return last, nil
+		},
+	},
+
+	Primitive{"flatten", 1, 1,
+		func(name string, args []Value) (Value, error) {
+			if err := checkArgType(name, args[0], isList); err != nil {
+				return nil, err
+			}
+			return listFromSlice(flatten(args[0], []Value{})), nil
+		},
+	},
+
+	Primitive{"any", 2, 2,
+		mkListSearch(func(b bool, v Value) (Value, bool) {
+			return NewBoolean(true), b
+		}, NewBoolean(false)),
+	},
+
+	Primitive{"every", 2, 2,
+		mkListSearch(func(b bool, v Value) (Value, bool) {
+			return NewBoolean(false), !b
+		}, NewBoolean(true)),
+	},
+
+	Primitive{"find", 2, 2,
+		mkListSearch(func(b bool, v Value) (Value, bool) {
+			return v, b
+		}, NewBoolean(false)),
+	},
+
+	Primitive{"remove", 2, 2,
+		func(name string, args []Value) (Value, error) {
+			if err := checkArgType(name, args[0], isFunction); err != nil {
+				return nil, err
+			}
+			items, err := seqToSlice(name, args[1])
+			if err != nil {
+				return nil, err
+			}
+			result := []Value{}
+			for _, item := range items {
+				v, err := applyFirst(args[0], []Value{item})
+				if err != nil {
+					return nil, err
+				}
+				if !v.isTrue() {
+					result = append(result, item)
+				}
+			}
+			return sameKind(args[1], result)
+		},
+	},
+
+	Primitive{"partition", 2, 2,
+		func(name string, args []Value) (Value, error) {
+			// returns (list matching not-matching)
+			if err := checkArgType(name, args[0], isFunction); err != nil {
+				return nil, err
+			}
+			items, err := seqToSlice(name, args[1])
+			if err != nil {
+				return nil, err
+			}
+			yes := []Value{}
+			no := []Value{}
+			for _, item := range items {
+				v, err := applyFirst(args[0], []Value{item})
+				if err != nil {
+					return nil, err
+				}
+				if v.isTrue() {
+					yes = append(yes, item)
+				} else {
+					no = append(no, item)
+				}
+			}
+			return listFromSlice([]Value{listFromSlice(yes), listFromSlice(no)}), nil
+		},
+	},
+
+	Primitive{"group-by", 2, 2,
+		func(name string, args []Value) (Value, error) {
+			// returns a list of (key items) in order of first appearance
+			if err := checkArgType(name, args[0], isFunction); err != nil {
+				return nil, err
+			}
+			items, err := seqToSlice(name, args[1])
+			if err != nil {
+				return nil, err
+			}
+			keys := []Value{}
+			groups := [][]Value{}
+			for _, item := range items {
+				key, err := applyFirst(args[0], []Value{item})
+				if err != nil {
+					return nil, err
+				}
+				found := false
+				for i := range keys {
+					if keys[i].isEqual(key) {
+						groups[i] = append(groups[i], item)
+						found = true
+						break
+					}
+				}
+				if !found {
+					keys = append(keys, key)
+					groups = append(groups, []Value{item})
+				}
+			}
+			result := make([]Value, len(keys))
+			for i := range keys {
+				result[i] = listFromSlice([]Value{keys[i], listFromSlice(groups[i])})
+			}
+			return listFromSlice(result), nil
+		},
+	},
+
+	Primitive{"distinct", 1, 1,
+		func(name string, args []Value) (Value, error) {
+			items, err := seqToSlice(name, args[0])
+			if err != nil {
+				return nil, err
+			}
+			result := []Value{}
+			for _, item := range items {
+				seen := false
+				for _, r := range result {
+					if r.isEqual(item) {
+						seen = true
+						break
+					}
+				}
+				if !seen {
+					result = append(result, item)
+				}
+			}
+			return sameKind(args[0], result)
+		},
+	},
+}
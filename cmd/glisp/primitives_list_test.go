@@ -0,0 +1,37 @@
+package main
+
+import "testing"
+
+func TestListPrimitives(t *testing.T) {
+	checkEval(t, `(sort '(3 1 2))`, `(1 2 3)`)
+	checkEval(t, `(sort '(3 1 2) (fn (a b) (> a b)))`, `(3 2 1)`)
+	checkEvalError(t, `(sort (list 1 "a"))`, "sort - cannot compare")
+	checkEval(t, `(member 2 '(1 2 3))`, `(2 3)`)
+	checkEval(t, `(member 4 '(1 2 3))`, `#f`)
+	checkEval(t, `(range 3)`, `(0 1 2)`)
+	checkEval(t, `(range 1 7 2)`, `(1 3 5)`)
+	checkEval(t, `(take 2 '(1 2 3))`, `(1 2)`)
+	checkEval(t, `(drop 2 '(1 2 3))`, `(3)`)
+	checkEval(t, `(zip '(1 2 3) '(a b))`, `((1 a) (2 b))`)
+	checkEval(t, `(last '(1 2 3))`, `3`)
+	checkEval(t, `(flatten '(1 (2 (3)) 4))`, `(1 2 3 4)`)
+	checkEval(t, `(any zero? '(1 0))`, `#t`)
+	checkEval(t, `(every zero? '(1 0))`, `#f`)
+	checkEval(t, `(find zero? '(1 0 2))`, `0`)
+	checkEval(t, `(remove zero? '(1 0 2))`, `(1 2)`)
+	checkEval(t, `(partition zero? '(1 0 2 0))`, `((0 0) (1 2))`)
+	checkEval(t, `(group-by (fn (x) (> x 1)) '(1 2 3))`, `((#f (1)) (#t (2 3)))`)
+	checkEval(t, `(distinct '(1 2 1 3 2))`, `(1 2 3)`)
+	checkEvalError(t, `(last '())`, "last")
+	checkEvalError(t, `(take 1 5)`, "take - wrong argument type")
+}
+
+func TestAssoc(t *testing.T) {
+	checkEval(t, `(assoc 'b '((a 1) (b 2)))`, `(b 2)`)
+	checkEval(t, `(assoc 'c '((a 1) (b 2)))`, `#f`)
+	checkEval(t, `(assoc [1 2] (list (list [1 2] 'found)))`, `([1 2] found)`)
+	checkEval(t, `(assoc {"a" 1} (list (list {"a" 1} 'found)))`, `({"a" 1} found)`)
+	checkEval(t, `(assoc [1 2] 0 5)`, `[5 2]`)
+	checkEval(t, `(get (assoc {"a" 1} "b" 2) "b")`, `2`)
+	checkEvalError(t, `(assoc {"a" 1} "b")`, "assoc - wrong argument type")
+}
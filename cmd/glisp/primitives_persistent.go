@@ -0,0 +1,280 @@
+package main
+
+import "fmt"
+
+func vectorArg(name string, args []Value, i int) (*vVector, error) {
+	p, ok := args[i].(*vVector)
+	if err := checkArgTypeB(name, args[i], ok); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func mapArg(name string, args []Value, i int) (*vMap, error) {
+	m, ok := args[i].(*vMap)
+	if err := checkArgTypeB(name, args[i], ok); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func isPersistent(v Value) bool {
+	switch v.(type) {
+	case *vVector, *vMap:
+		return true
+	}
+	return false
+}
+
+func persistentAssoc(name string, args []Value) (Value, error) {
+	// (assoc coll key value ...) for a vector or hash-map
+	if len(args)%2 != 1 {
+		return nil, fmt.Errorf("%s - key without a value", name)
+	}
+	if p, ok := args[0].(*vVector); ok {
+		content := p.content
+		for i := 1; i < len(args); i += 2 {
+			idx, ok := args[i].asInteger()
+			if err := checkArgTypeB(name, args[i], ok); err != nil {
+				return nil, err
+			}
+			if idx < 0 || idx > content.count {
+				return nil, fmt.Errorf("%s - vector index out of bounds %d", name, idx)
+			}
+			content = content.assocN(idx, args[i+1])
+		}
+		return &vVector{content}, nil
+	}
+	m, err := mapArg(name, args, 0)
+	if err != nil {
+		return nil, err
+	}
+	content := m.content
+	for i := 1; i < len(args); i += 2 {
+		content, err = content.assoc(args[i], args[i+1])
+		if err != nil {
+			return nil, fmt.Errorf("%s - %s", name, err.Error())
+		}
+	}
+	return NewMap(content), nil
+}
+
+func mapEntryArg(name string, v Value) (Value, Value, error) {
+	// a (key value) list or a [key value] vector
+	if p, ok := v.(*vVector); ok && p.content.count == 2 {
+		return p.content.nth(0), p.content.nth(1), nil
+	}
+	if key, rest, ok := v.asCons(); ok {
+		if value, rest, ok := rest.asCons(); ok && rest.isEmpty() {
+			return key, value, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("%s - hash-map entry not a pair %s", name, v.Display())
+}
+
+var PERSISTENT_PRIMITIVES = []Primitive{
+
+	Primitive{"vector", 0, -1,
+		func(name string, args []Value) (Value, error) {
+			content := make([]Value, len(args))
+			copy(content, args)
+			return NewVector(content), nil
+		},
+	},
+
+	Primitive{"vector?", 1, 1,
+		func(name string, args []Value) (Value, error) {
+			_, ok := args[0].(*vVector)
+			return NewBoolean(ok), nil
+		},
+	},
+
+	Primitive{"list->vector", 1, 1,
+		func(name string, args []Value) (Value, error) {
+			items, err := seqToSlice(name, args[0])
+			if err != nil {
+				return nil, err
+			}
+			return NewVector(items), nil
+		},
+	},
+
+	Primitive{"hash-map", 0, -1,
+		func(name string, args []Value) (Value, error) {
+			// (hash-map key value ...)
+			if len(args)%2 != 0 {
+				return nil, fmt.Errorf("%s - key without a value", name)
+			}
+			content := emptyPMap()
+			for i := 0; i < len(args); i += 2 {
+				next, err := content.assoc(args[i], args[i+1])
+				if err != nil {
+					return nil, fmt.Errorf("%s - %s", name, err.Error())
+				}
+				content = next
+			}
+			return NewMap(content), nil
+		},
+	},
+
+	Primitive{"hash-map?", 1, 1,
+		func(name string, args []Value) (Value, error) {
+			_, ok := args[0].(*vMap)
+			return NewBoolean(ok), nil
+		},
+	},
+
+	Primitive{"hash-map-keys", 1, 1,
+		func(name string, args []Value) (Value, error) {
+			m, err := mapArg(name, args, 0)
+			if err != nil {
+				return nil, err
+			}
+			entries := m.content.entries()
+			result := make([]Value, len(entries))
+			for i, e := range entries {
+				result[i] = e.key
+			}
+			return listFromSlice(result), nil
+		},
+	},
+
+	Primitive{"hash-map-values", 1, 1,
+		func(name string, args []Value) (Value, error) {
+			m, err := mapArg(name, args, 0)
+			if err != nil {
+				return nil, err
+			}
+			entries := m.content.entries()
+			result := make([]Value, len(entries))
+			for i, e := range entries {
+				result[i] = e.value
+			}
+			return listFromSlice(result), nil
+		},
+	},
+
+	Primitive{"dissoc", 1, -1,
+		func(name string, args []Value) (Value, error) {
+			m, err := mapArg(name, args, 0)
+			if err != nil {
+				return nil, err
+			}
+			content := m.content
+			for _, key := range args[1:] {
+				content, err = content.dissoc(key)
+				if err != nil {
+					return nil, fmt.Errorf("%s - %s", name, err.Error())
+				}
+			}
+			return NewMap(content), nil
+		},
+	},
+
+	Primitive{"conj", 1, -1,
+		func(name string, args []Value) (Value, error) {
+			// add items where the collection grows best: at the end of
+			// a vector, at the front of a list, as entries of a hash-map
+			if p, ok := args[0].(*vVector); ok {
+				content := p.content
+				for _, item := range args[1:] {
+					content = content.conj(item)
+				}
+				return &vVector{content}, nil
+			}
+			if m, ok := args[0].(*vMap); ok {
+				content := m.content
+				for _, item := range args[1:] {
+					key, value, err := mapEntryArg(name, item)
+					if err != nil {
+						return nil, err
+					}
+					content, err = content.assoc(key, value)
+					if err != nil {
+						return nil, fmt.Errorf("%s - %s", name, err.Error())
+					}
+				}
+				return NewMap(content), nil
+			}
+			if err := checkArgType(name, args[0], isList); err != nil {
+				return nil, err
+			}
+			result := args[0]
+			for _, item := range args[1:] {
+				result = NewCons(item, result)
+			}
+			return result, nil
+		},
+	},
+
+	Primitive{"get", 2, 3,
+		func(name string, args []Value) (Value, error) {
+			// (get coll key [default]) on vectors, hash-maps, arrays and
+			// dicts; missing keys give the default, or #f without one
+			var result Value
+			found := false
+			if p, ok := args[0].(*vVector); ok {
+				if idx, ok := args[1].asInteger(); ok && idx >= 0 && idx < p.content.count {
+					result, found = p.content.nth(idx), true
+				}
+			} else if content, ok := args[0].asArray(); ok {
+				if idx, ok := args[1].asInteger(); ok && idx >= 0 && idx < len(content) {
+					result, found = content[idx], true
+				}
+			} else if m, ok := args[0].(*vMap); ok {
+				v, ok, err := m.content.get(args[1])
+				if err != nil {
+					return nil, fmt.Errorf("%s - %s", name, err.Error())
+				}
+				result, found = v, ok
+			} else if content, ok := args[0].asDict(); ok {
+				v, ok, err := content.get(args[1])
+				if err != nil {
+					return nil, fmt.Errorf("%s - %s", name, err.Error())
+				}
+				result, found = v, ok
+			} else {
+				return nil, fmt.Errorf("%s - wrong argument type %s", name, args[0].typ())
+			}
+			if found {
+				return result, nil
+			}
+			if len(args) > 2 {
+				return args[2], nil
+			}
+			return NewBoolean(false), nil
+		},
+	},
+
+	Primitive{"contains?", 2, 2,
+		func(name string, args []Value) (Value, error) {
+			// index in range for a vector, key present for a hash-map
+			if p, ok := args[0].(*vVector); ok {
+				idx, ok := args[1].asInteger()
+				return NewBoolean(ok && idx >= 0 && idx < p.content.count), nil
+			}
+			m, err := mapArg(name, args, 0)
+			if err != nil {
+				return nil, err
+			}
+			_, ok, err := m.content.get(args[1])
+			if err != nil {
+				return nil, fmt.Errorf("%s - %s", name, err.Error())
+			}
+			return NewBoolean(ok), nil
+		},
+	},
+
+	Primitive{"pop", 1, 1,
+		func(name string, args []Value) (Value, error) {
+			p, err := vectorArg(name, args, 0)
+			if err != nil {
+				return nil, err
+			}
+			if p.content.count == 0 {
+				return nil, fmt.Errorf("%s - empty vector", name)
+			}
+			return &vVector{p.content.pop()}, nil
+		},
+	},
+}
@@ -0,0 +1,21 @@
+package main
+
+import "testing"
+
+func TestPersistentPrimitives(t *testing.T) {
+	checkEval(t, `(vector 1 2 3)`, `[1 2 3]`)
+	checkEval(t, `(list (vector? [1]) (vector? '(1)))`, `(#t #f)`)
+	checkEval(t, `(list->vector '(1 2))`, `[1 2]`)
+	checkEval(t, `(hash-map-keys (hash-map 'a 1 'b 2))`, `(a b)`)
+	checkEval(t, `(hash-map-values (hash-map 'a 1 'b 2))`, `(1 2)`)
+	checkEval(t, `(dissoc {'a 1 'b 2} 'a)`, `{b 2}`)
+	checkEval(t, `(conj [1 2] 3 4)`, `[1 2 3 4]`)
+	checkEval(t, `(list (get [1 2] 1) (get [1 2] 5 'none) (get {'a 1} 'b 0))`, `(2 none 0)`)
+	checkEval(t, `(list (contains? {'a 1} 'a) (contains? [1 2] 2))`, `(#t #f)`)
+	checkEval(t, `(pop [1 2 3])`, `[1 2]`)
+	// updates leave the original untouched
+	checkEval(t, `(def v [1 2]) (def w (conj v 3)) (list v w)`, `([1 2] [1 2 3])`)
+	checkEval(t, `(def m {'a 1}) (def n (assoc m 'b 2)) (list (hash-map-keys m) (hash-map-keys n))`, `((a) (a b))`)
+	checkEvalError(t, `(hash-map 'a)`, "hash-map")
+	checkEvalError(t, `(hash-map (array) 1)`, "not hashable")
+}
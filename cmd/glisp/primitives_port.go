@@ -0,0 +1,329 @@
+package main
+
+import "fmt"
+import "io"
+import "os"
+import "strings"
+
+func portArg(name string, args []Value, i int, def *vPort) (*vPort, error) {
+	// optional port argument at position i
+	if len(args) <= i {
+		return def, nil
+	}
+	p, ok := args[i].asPort()
+	if err := checkArgTypeB(name, args[i], ok); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func portError(name string, err error) error {
+	return fmt.Errorf("%s - %s", name, err.Error())
+}
+
+func openFilePort(path string, mode string) (*vPort, error) {
+	switch mode {
+	case "read":
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		return NewInputPort(path, f, f), nil
+	case "write":
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+		if err != nil {
+			return nil, err
+		}
+		return NewOutputPort(path, f, f), nil
+	case "append":
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+		if err != nil {
+			return nil, err
+		}
+		return NewOutputPort(path, f, f), nil
+	}
+	return nil, fmt.Errorf("unknown file mode %s", mode)
+}
+
+func portPrimitives(st *engineState) []Primitive {
+	return []Primitive{
+
+		Primitive{"open-input-file", 1, 1,
+			func(name string, args []Value) (Value, error) {
+				path, err := st.pathArg(name, args, 0)
+				if err != nil {
+					return nil, err
+				}
+				p, err := openFilePort(path, "read")
+				if err != nil {
+					return nil, portError(name, err)
+				}
+				return p, nil
+			},
+		},
+
+		Primitive{"open-output-file", 1, 2,
+			func(name string, args []Value) (Value, error) {
+				// (open-output-file path) truncates, (open-output-file path #t) appends
+				path, err := st.pathArg(name, args, 0)
+				if err != nil {
+					return nil, err
+				}
+				mode := "write"
+				if len(args) > 1 && args[1].isTrue() {
+					mode = "append"
+				}
+				p, err := openFilePort(path, mode)
+				if err != nil {
+					return nil, portError(name, err)
+				}
+				return p, nil
+			},
+		},
+
+		Primitive{"open-input-string", 1, 1,
+			func(name string, args []Value) (Value, error) {
+				str, ok := args[0].asString()
+				if err := checkArgTypeB(name, args[0], ok); err != nil {
+					return nil, err
+				}
+				return NewInputPort("string", strings.NewReader(str), nil), nil
+			},
+		},
+
+		Primitive{"open-output-string", 0, 0,
+			func(name string, args []Value) (Value, error) {
+				return NewOutputPort("string", &strings.Builder{}, nil), nil
+			},
+		},
+
+		Primitive{"get-output-string", 1, 1,
+			func(name string, args []Value) (Value, error) {
+				p, err := portArg(name, args, 0, nil)
+				if err != nil {
+					return nil, err
+				}
+				str, err := p.outputString()
+				if err != nil {
+					return nil, portError(name, err)
+				}
+				return NewString(str), nil
+			},
+		},
+
+		Primitive{"call-with-open-file", 3, 3,
+			func(name string, args []Value) (Value, error) {
+				// (call-with-open-file path mode f) with mode one of
+				// read, write or append; the port is closed when f returns
+				path, err := st.pathArg(name, args, 0)
+				if err != nil {
+					return nil, err
+				}
+				mode, ok := args[1].asSymbol()
+				if err := checkArgTypeB(name, args[1], ok); err != nil {
+					return nil, err
+				}
+				if err := checkArgType(name, args[2], isFunction); err != nil {
+					return nil, err
+				}
+				p, err := openFilePort(path, mode)
+				if err != nil {
+					return nil, portError(name, err)
+				}
+				result, err := args[2].apply([]Value{p})
+				if closeErr := p.close(); err == nil && closeErr != nil {
+					return nil, portError(name, closeErr)
+				}
+				return result, err
+			},
+		},
+
+		Primitive{"read-line", 0, 1,
+			func(name string, args []Value) (Value, error) {
+				p, err := portArg(name, args, 0, st.stdin)
+				if err != nil {
+					return nil, err
+				}
+				line, err := p.readLine()
+				if err == io.EOF {
+					return NewEOF(), nil
+				}
+				if err != nil {
+					return nil, portError(name, err)
+				}
+				line = strings.TrimSuffix(line, "\n")
+				return NewString(strings.TrimSuffix(line, "\r")), nil
+			},
+		},
+
+		Primitive{"lines", 0, 1,
+			func(name string, args []Value) (Value, error) {
+				// a lazy sequence of the lines read from a port
+				p, err := portArg(name, args, 0, st.stdin)
+				if err != nil {
+					return nil, err
+				}
+				return NewLazySeq(func() (Value, bool, error) {
+					line, err := p.readLine()
+					if err == io.EOF {
+						return nil, false, nil
+					}
+					if err != nil {
+						return nil, false, portError(name, err)
+					}
+					line = strings.TrimSuffix(line, "\n")
+					return NewString(strings.TrimSuffix(line, "\r")), true, nil
+				}), nil
+			},
+		},
+
+		Primitive{"read-char", 0, 1,
+			func(name string, args []Value) (Value, error) {
+				p, err := portArg(name, args, 0, st.stdin)
+				if err != nil {
+					return nil, err
+				}
+				r, err := p.readRune()
+				if err == io.EOF {
+					return NewEOF(), nil
+				}
+				if err != nil {
+					return nil, portError(name, err)
+				}
+				return NewChar(r), nil
+			},
+		},
+
+		Primitive{"read-all", 0, 1,
+			func(name string, args []Value) (Value, error) {
+				p, err := portArg(name, args, 0, st.stdin)
+				if err != nil {
+					return nil, err
+				}
+				str, err := p.readAll()
+				if err != nil {
+					return nil, portError(name, err)
+				}
+				return NewString(str), nil
+			},
+		},
+
+		Primitive{"read", 0, 1,
+			func(name string, args []Value) (Value, error) {
+				p, err := portArg(name, args, 0, st.stdin)
+				if err != nil {
+					return nil, err
+				}
+				v, err := p.readValue()
+				if err != nil {
+					return nil, portError(name, err)
+				}
+				if v == nil {
+					return NewEOF(), nil
+				}
+				return v, nil
+			},
+		},
+
+		Primitive{"write-string", 1, 2,
+			func(name string, args []Value) (Value, error) {
+				str, ok := args[0].asString()
+				if err := checkArgTypeB(name, args[0], ok); err != nil {
+					return nil, err
+				}
+				p, err := portArg(name, args, 1, st.stdout)
+				if err != nil {
+					return nil, err
+				}
+				if err := p.write(str); err != nil {
+					return nil, portError(name, err)
+				}
+				return NewNil(), nil
+			},
+		},
+
+		Primitive{"write-line", 1, 2,
+			func(name string, args []Value) (Value, error) {
+				str, ok := args[0].asString()
+				if err := checkArgTypeB(name, args[0], ok); err != nil {
+					return nil, err
+				}
+				p, err := portArg(name, args, 1, st.stdout)
+				if err != nil {
+					return nil, err
+				}
+				if err := p.write(str + "\n"); err != nil {
+					return nil, portError(name, err)
+				}
+				return NewNil(), nil
+			},
+		},
+
+		Primitive{"write", 1, 2,
+			func(name string, args []Value) (Value, error) {
+				p, err := portArg(name, args, 1, st.stdout)
+				if err != nil {
+					return nil, err
+				}
+				if err := p.write(args[0].Display()); err != nil {
+					return nil, portError(name, err)
+				}
+				return NewNil(), nil
+			},
+		},
+
+		Primitive{"pp", 1, 2,
+			func(name string, args []Value) (Value, error) {
+				width := terminalWidth()
+				if len(args) > 1 {
+					w, ok := args[1].asInteger()
+					if err := checkArgTypeB(name, args[1], ok); err != nil {
+						return nil, err
+					}
+					width = w
+				}
+				if err := st.stdout.write(prettyDisplay(args[0], width) + "\n"); err != nil {
+					return nil, portError(name, err)
+				}
+				return NewNil(), nil
+			},
+		},
+
+		Primitive{"close", 1, 1,
+			func(name string, args []Value) (Value, error) {
+				p, err := portArg(name, args, 0, nil)
+				if err != nil {
+					return nil, err
+				}
+				if p == st.stdin {
+					// the REPL reads its input from it
+					return nil, fmt.Errorf("%s - cannot close stdin", name)
+				}
+				if err := p.close(); err != nil {
+					return nil, portError(name, err)
+				}
+				return NewNil(), nil
+			},
+		},
+
+		Primitive{"port?", 1, 1,
+			func(name string, args []Value) (Value, error) {
+				_, ok := args[0].asPort()
+				return NewBoolean(ok), nil
+			},
+		},
+
+		Primitive{"eof-object", 0, 0,
+			func(name string, args []Value) (Value, error) {
+				return NewEOF(), nil
+			},
+		},
+
+		Primitive{"eof?", 1, 1,
+			func(name string, args []Value) (Value, error) {
+				_, ok := args[0].(*vEOF)
+				return NewBoolean(ok), nil
+			},
+		},
+	}
+}
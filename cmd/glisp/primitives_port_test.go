@@ -0,0 +1,43 @@
+package main
+
+import "fmt"
+import "path/filepath"
+import "strings"
+import "testing"
+
+func TestStringPorts(t *testing.T) {
+	checkEval(t, `(let ((p (open-input-string (string-append "ab" (list->string (list #\newline)) "cd")))) (list (read-char p) (read-line p) (read-line p) (eof? (read-line p))))`, `(#\a "b" "cd" #t)`)
+	checkEval(t, `(let ((p (open-output-string))) (do (write-string "a" p) (write-line "b" p) (write '(1 "c") p) (get-output-string p)))`, "\"ab\n(1 \"c\")\"")
+	checkEval(t, `(read (open-input-string "(1 (2 3)) x"))`, `(1 (2 3))`)
+	checkEval(t, `(let ((p (open-input-string "1 (2"))) (list (read p) (eof? (read-all p))))`, `(1 #f)`)
+	checkEval(t, `(list (port? stdin) (port? "a") (eof? (eof-object)))`, `(#t #f #t)`)
+}
+
+func TestWithOpenFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out")
+	checkEval(t, fmt.Sprintf(`(do (with-open-file (p %q 'write) (write-line "hello" p)) (with-open-file (p %q) (read-line p)))`, path, path), `"hello"`)
+	checkEval(t, fmt.Sprintf(`(do (with-open-file (p %q 'append) (write-line "world" p)) (with-open-file (p %q) (read-all p)))`, path, path), "\"hello\nworld\n\"")
+	// the port is closed when the body returns
+	checkEvalError(t, fmt.Sprintf(`(read-line (with-open-file (p %q) p))`, path), "closed")
+	// and when it fails
+	e := NewEngine()
+	if _, err := evalSource(e, fmt.Sprintf(`(def q (ref #f)) (with-open-file (p %q) (do (q p) (car '())))`, path)); err == nil {
+		t.Errorf("expected the body of with-open-file to fail")
+	}
+	if _, err := evalSource(e, `(read-line (q))`); err == nil || !strings.Contains(err.Error(), "closed") {
+		t.Errorf("expected the port to be closed but got %v", err)
+	}
+	checkEval(t, fmt.Sprintf(`(let ((call-with-open-file 5)) (with-open-file (p %q) (read-line p)))`, path), `"hello"`)
+}
+
+func TestPrettyPrintWritesToStdoutPort(t *testing.T) {
+	var out strings.Builder
+	e := NewEngine()
+	e.state.stdout = NewOutputPort("test", &out, nil)
+	if _, err := evalSource(e, `(pp '(1 2) 3)`); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "(1\n 2)\n" {
+		t.Errorf("expected pp to write to the stdout port but got %q", out.String())
+	}
+}
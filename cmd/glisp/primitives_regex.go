@@ -0,0 +1,227 @@
+package main
+
+import "fmt"
+import "regexp"
+
+func regexArg(name string, arg Value) (*regexp.Regexp, error) {
+	// accept either a regex value or a string to compile
+	if r, ok := arg.asRegex(); ok {
+		return r.re, nil
+	}
+	str, ok := arg.asString()
+	if err := checkArgTypeB(name, arg, ok); err != nil {
+		return nil, err
+	}
+	re, err := regexp.Compile(str)
+	if err != nil {
+		return nil, fmt.Errorf("%s - %s", name, err.Error())
+	}
+	return re, nil
+}
+
+func regexStringArgs(name string, args []Value) (*regexp.Regexp, string, error) {
+	re, err := regexArg(name, args[0])
+	if err != nil {
+		return nil, "", err
+	}
+	str, ok := args[1].asString()
+	if err := checkArgTypeB(name, args[1], ok); err != nil {
+		return nil, "", err
+	}
+	return re, str, nil
+}
+
+func countArg(name string, args []Value, i int) (int, error) {
+	// optional count at position i, defaulting to -1 for all
+	if len(args) <= i {
+		return -1, nil
+	}
+	n, ok := args[i].asInteger()
+	if err := checkArgTypeB(name, args[i], ok); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+func submatchList(str string, idx []int) Value {
+	// unmatched groups are reported as #f
+	groups := make([]Value, len(idx)/2)
+	for i := range groups {
+		if idx[2*i] < 0 {
+			groups[i] = NewBoolean(false)
+		} else {
+			groups[i] = NewString(str[idx[2*i]:idx[2*i+1]])
+		}
+	}
+	return listFromSlice(groups)
+}
+
+var REGEX_PRIMITIVES = []Primitive{
+
+	Primitive{"regex", 1, 1,
+		func(name string, args []Value) (Value, error) {
+			re, err := regexArg(name, args[0])
+			if err != nil {
+				return nil, err
+			}
+			return NewRegex(re), nil
+		},
+	},
+
+	Primitive{"regex?", 1, 1,
+		func(name string, args []Value) (Value, error) {
+			_, ok := args[0].asRegex()
+			return NewBoolean(ok), nil
+		},
+	},
+
+	Primitive{"regex-match?", 2, 2,
+		func(name string, args []Value) (Value, error) {
+			re, str, err := regexStringArgs(name, args)
+			if err != nil {
+				return nil, err
+			}
+			return NewBoolean(re.MatchString(str)), nil
+		},
+	},
+
+	Primitive{"regex-find", 2, 2,
+		func(name string, args []Value) (Value, error) {
+			re, str, err := regexStringArgs(name, args)
+			if err != nil {
+				return nil, err
+			}
+			idx := re.FindStringIndex(str)
+			if idx == nil {
+				return NewBoolean(false), nil
+			}
+			return NewString(str[idx[0]:idx[1]]), nil
+		},
+	},
+
+	Primitive{"regex-find-all", 2, 3,
+		func(name string, args []Value) (Value, error) {
+			re, str, err := regexStringArgs(name, args)
+			if err != nil {
+				return nil, err
+			}
+			n, err := countArg(name, args, 2)
+			if err != nil {
+				return nil, err
+			}
+			return stringsToList(re.FindAllString(str, n)), nil
+		},
+	},
+
+	Primitive{"regex-submatch", 2, 2,
+		func(name string, args []Value) (Value, error) {
+			re, str, err := regexStringArgs(name, args)
+			if err != nil {
+				return nil, err
+			}
+			idx := re.FindStringSubmatchIndex(str)
+			if idx == nil {
+				return NewBoolean(false), nil
+			}
+			return submatchList(str, idx), nil
+		},
+	},
+
+	Primitive{"regex-submatch-all", 2, 3,
+		func(name string, args []Value) (Value, error) {
+			re, str, err := regexStringArgs(name, args)
+			if err != nil {
+				return nil, err
+			}
+			n, err := countArg(name, args, 2)
+			if err != nil {
+				return nil, err
+			}
+			all := re.FindAllStringSubmatchIndex(str, n)
+			matches := make([]Value, len(all))
+			for i, idx := range all {
+				matches[i] = submatchList(str, idx)
+			}
+			return listFromSlice(matches), nil
+		},
+	},
+
+	Primitive{"regex-submatch-dict", 2, 2,
+		func(name string, args []Value) (Value, error) {
+			re, str, err := regexStringArgs(name, args)
+			if err != nil {
+				return nil, err
+			}
+			idx := re.FindStringSubmatchIndex(str)
+			if idx == nil {
+				return NewBoolean(false), nil
+			}
+			groups := []string{}
+			values := []Value{}
+			for i, group := range re.SubexpNames() {
+				if group == "" {
+					continue
+				}
+				groups = append(groups, group)
+				if idx[2*i] < 0 {
+					values = append(values, NewBoolean(false))
+				} else {
+					values = append(values, NewString(str[idx[2*i]:idx[2*i+1]]))
+				}
+			}
+			return symbolDict(groups, values), nil
+		},
+	},
+
+	Primitive{"regex-replace", 3, 3,
+		func(name string, args []Value) (Value, error) {
+			// the replacement is either a template string with $1 or ${name}
+			// references, or a function called on each matched string
+			re, str, err := regexStringArgs(name, args)
+			if err != nil {
+				return nil, err
+			}
+			if tmpl, ok := args[2].asString(); ok {
+				return NewString(re.ReplaceAllString(str, tmpl)), nil
+			}
+			if err := checkArgType(name, args[2], isFunction); err != nil {
+				return nil, err
+			}
+			var callbackErr error
+			result := re.ReplaceAllStringFunc(str, func(match string) string {
+				if callbackErr != nil {
+					return match
+				}
+				v, err := applyFirst(args[2], []Value{NewString(match)})
+				if err != nil {
+					callbackErr = err
+					return match
+				}
+				replacement, ok := v.asString()
+				if !ok {
+					callbackErr = fmt.Errorf("%s - replacement is not a string %s", name, v.Display())
+					return match
+				}
+				return replacement
+			})
+			if callbackErr != nil {
+				return nil, callbackErr
+			}
+			return NewString(result), nil
+		},
+	},
+
+	Primitive{"regex-split", 2, 3,
+		func(name string, args []Value) (Value, error) {
+			re, str, err := regexStringArgs(name, args)
+			if err != nil {
+				return nil, err
+			}
+			n, err := countArg(name, args, 2)
+			if err != nil {
+				return nil, err
+			}
+			return stringsToList(re.Split(str, n)), nil
+		},
+	},
+}
@@ -0,0 +1,20 @@
+package main
+
+import "testing"
+
+func TestRegexPrimitives(t *testing.T) {
+	checkEval(t, `(regex-match? (regex "^a+$") "aaa")`, `#t`)
+	checkEval(t, `(regex-match? "^a+$" "aab")`, `#f`)
+	checkEval(t, `(regex-find #r"\d+" "ab12cd345")`, `"12"`)
+	checkEval(t, `(regex-find #r"\d+" "abcd")`, `#f`)
+	checkEval(t, `(regex-find-all #r"\d+" "ab12cd345e6")`, `("12" "345" "6")`)
+	checkEval(t, `(regex-find-all #r"\d+" "ab12cd345e6" 2)`, `("12" "345")`)
+	checkEval(t, `(regex-submatch #r"(\w+)@(\w+)?" "me@")`, `("me@" "me" #f)`)
+	checkEval(t, `(regex-submatch-all #r"(\d)(\w)" "1a2b")`, `(("1a" "1" "a") ("2b" "2" "b"))`)
+	checkEval(t, `(regex-replace #r"(\d+)" "a1b22" "<$1>")`, `"a<1>b<22>"`)
+	checkEval(t, `(regex-replace #r"\d+" "a1b22" (fn (m) (string-repeat m 2)))`, `"a11b2222"`)
+	checkEval(t, `(regex-split #r"\s*,\s*" "a , b,c")`, `("a" "b" "c")`)
+	checkEval(t, `(regex? "a")`, `#f`)
+	checkEvalError(t, `(regex "(")`, "regex - error parsing regexp")
+	checkEvalError(t, `(regex-replace #r"a" "a" (fn (m) 1))`, "regex-replace - replacement is not a string 1")
+}
@@ -0,0 +1,217 @@
+package main
+
+import "fmt"
+
+func setFromValues(vs []Value) (*pmap, error) {
+	result := emptyPMap()
+	for _, v := range vs {
+		next, err := result.assoc(v, v)
+		if err != nil {
+			return nil, err
+		}
+		result = next
+	}
+	return result, nil
+}
+
+func isSubset(m1 *pmap, m2 *pmap) bool {
+	if m1.count > m2.count {
+		return false
+	}
+	for _, e := range m1.entries() {
+		if _, ok, _ := m2.get(e.key); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func setArg(name string, args []Value, i int) (*pmap, error) {
+	s, ok := args[i].(*vSet)
+	if err := checkArgTypeB(name, args[i], ok); err != nil {
+		return nil, err
+	}
+	return s.content, nil
+}
+
+func setArgs(name string, args []Value) ([]*pmap, error) {
+	sets := make([]*pmap, len(args))
+	for i := range args {
+		s, err := setArg(name, args, i)
+		if err != nil {
+			return nil, err
+		}
+		sets[i] = s
+	}
+	return sets, nil
+}
+
+func mkSetUpdate(update func(*pmap, Value) (*pmap, error)) func(string, []Value) (Value, error) {
+	return func(name string, args []Value) (Value, error) {
+		s, err := setArg(name, args, 0)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range args[1:] {
+			s, err = update(s, v)
+			if err != nil {
+				return nil, fmt.Errorf("%s - %s", name, err.Error())
+			}
+		}
+		return NewSet(s), nil
+	}
+}
+
+func mkSetFilter(keep func(Value, []*pmap) bool) func(string, []Value) (Value, error) {
+	// elements of the first set for which keep holds against the others
+	return func(name string, args []Value) (Value, error) {
+		sets, err := setArgs(name, args)
+		if err != nil {
+			return nil, err
+		}
+		result := emptyPMap()
+		for _, e := range sets[0].entries() {
+			if keep(e.key, sets[1:]) {
+				result, _ = result.assoc(e.key, e.key)
+			}
+		}
+		return NewSet(result), nil
+	}
+}
+
+func mkSetComparison(test func(*pmap, *pmap) bool) func(string, []Value) (Value, error) {
+	return func(name string, args []Value) (Value, error) {
+		sets, err := setArgs(name, args)
+		if err != nil {
+			return nil, err
+		}
+		return NewBoolean(test(sets[0], sets[1])), nil
+	}
+}
+
+var SET_PRIMITIVES = []Primitive{
+
+	Primitive{"set", 0, -1,
+		func(name string, args []Value) (Value, error) {
+			s, err := setFromValues(args)
+			if err != nil {
+				return nil, fmt.Errorf("%s - %s", name, err.Error())
+			}
+			return NewSet(s), nil
+		},
+	},
+
+	Primitive{"set?", 1, 1,
+		func(name string, args []Value) (Value, error) {
+			_, ok := args[0].(*vSet)
+			return NewBoolean(ok), nil
+		},
+	},
+
+	Primitive{"set-add", 1, -1,
+		mkSetUpdate(func(s *pmap, v Value) (*pmap, error) {
+			return s.assoc(v, v)
+		}),
+	},
+
+	Primitive{"set-remove", 1, -1,
+		mkSetUpdate(func(s *pmap, v Value) (*pmap, error) {
+			return s.dissoc(v)
+		}),
+	},
+
+	Primitive{"set-member?", 2, 2,
+		func(name string, args []Value) (Value, error) {
+			s, err := setArg(name, args, 0)
+			if err != nil {
+				return nil, err
+			}
+			_, ok, err := s.get(args[1])
+			if err != nil {
+				return nil, fmt.Errorf("%s - %s", name, err.Error())
+			}
+			return NewBoolean(ok), nil
+		},
+	},
+
+	Primitive{"union", 0, -1,
+		func(name string, args []Value) (Value, error) {
+			sets, err := setArgs(name, args)
+			if err != nil {
+				return nil, err
+			}
+			result := emptyPMap()
+			for _, s := range sets {
+				if s.count > result.count {
+					// add the smaller one into the larger one
+					result, s = s, result
+				}
+				for _, e := range s.entries() {
+					result, _ = result.assoc(e.key, e.key)
+				}
+			}
+			return NewSet(result), nil
+		},
+	},
+
+	Primitive{"intersection", 1, -1,
+		mkSetFilter(func(v Value, others []*pmap) bool {
+			for _, s := range others {
+				if _, ok, _ := s.get(v); !ok {
+					return false
+				}
+			}
+			return true
+		}),
+	},
+
+	Primitive{"difference", 1, -1,
+		mkSetFilter(func(v Value, others []*pmap) bool {
+			for _, s := range others {
+				if _, ok, _ := s.get(v); ok {
+					return false
+				}
+			}
+			return true
+		}),
+	},
+
+	Primitive{"subset?", 2, 2,
+		mkSetComparison(isSubset),
+	},
+
+	Primitive{"superset?", 2, 2,
+		mkSetComparison(func(s1 *pmap, s2 *pmap) bool {
+			return isSubset(s2, s1)
+		}),
+	},
+
+	Primitive{"set->list", 1, 1,
+		func(name string, args []Value) (Value, error) {
+			s, err := setArg(name, args, 0)
+			if err != nil {
+				return nil, err
+			}
+			entries := s.entries()
+			result := make([]Value, len(entries))
+			for i, e := range entries {
+				result[i] = e.key
+			}
+			return listFromSlice(result), nil
+		},
+	},
+
+	Primitive{"list->set", 1, 1,
+		func(name string, args []Value) (Value, error) {
+			items, err := seqToSlice(name, args[0])
+			if err != nil {
+				return nil, err
+			}
+			s, err := setFromValues(items)
+			if err != nil {
+				return nil, fmt.Errorf("%s - %s", name, err.Error())
+			}
+			return NewSet(s), nil
+		},
+	},
+}
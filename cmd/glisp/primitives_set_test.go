@@ -0,0 +1,21 @@
+package main
+
+import "testing"
+
+func TestSetPrimitives(t *testing.T) {
+	checkEval(t, `(length (set 1 2 1))`, `2`)
+	checkEval(t, `(list (set? #{1}) (set? '(1)))`, `(#t #f)`)
+	checkEval(t, `(equal? (set-add #{1} 2 3) #{1 2 3})`, `#t`)
+	checkEval(t, `(set-remove #{1 2 3} 2)`, `#{1 3}`)
+	checkEval(t, `(list (set-member? #{1 2} 2) (set-member? #{1 2} 3))`, `(#t #f)`)
+	checkEval(t, `(equal? (union #{1 2} #{2 3}) #{1 2 3})`, `#t`)
+	checkEval(t, `(intersection #{1 2 3} #{2 3 4})`, `#{2 3}`)
+	checkEval(t, `(difference #{1 2 3} #{2})`, `#{1 3}`)
+	checkEval(t, `(list (subset? #{1} #{1 2}) (superset? #{1} #{1 2}))`, `(#t #f)`)
+	checkEval(t, `(sort (set->list #{3 1 2}))`, `(1 2 3)`)
+	checkEval(t, `(equal? (list->set '(1 1 2)) #{1 2})`, `#t`)
+	checkEval(t, `(equal? #{1 2} #{2 1})`, `#t`)
+	checkEval(t, `(= (hash #{1 2}) (hash #{2 1}))`, `#t`)
+	checkEval(t, `(def s #{1}) (def u (set-add s 2)) (list (length s) (length u))`, `(1 2)`)
+	checkEvalError(t, `(set (array))`, "not hashable")
+}
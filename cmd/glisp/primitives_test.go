@@ -0,0 +1,48 @@
+package main
+
+import "testing"
+
+func TestStringPrimitives(t *testing.T) {
+	checkEval(t, `(string-split "a b  c")`, `("a" "b" "c")`)
+	checkEval(t, `(string-split "a,b,,c" ",")`, `("a" "b" "" "c")`)
+	checkEval(t, `(string-lines "abc")`, `("abc")`)
+	checkEval(t, `(string-join (list "a" "b" "c") ", ")`, `"a, b, c"`)
+	checkEval(t, `(string-join (list "a" "b"))`, `"ab"`)
+	checkEval(t, `(string-trim "  a b  ")`, `"a b"`)
+	checkEval(t, `(string-trim "xxaxx" "x")`, `"a"`)
+	checkEval(t, `(string-trim-left "xxaxx" "x")`, `"axx"`)
+	checkEval(t, `(string-trim-right "  a  ")`, `"  a"`)
+	checkEval(t, `(string-index "hello" "ll")`, `2`)
+	checkEval(t, `(string-index "hello" "z")`, `#f`)
+	checkEval(t, `(list (string-contains? "hello" "ell") (string-prefix? "hello" "he") (string-suffix? "hello" "he"))`, `(#t #t #f)`)
+	checkEval(t, `(string-replace "aaa" "a" "b")`, `"bbb"`)
+	checkEval(t, `(string-replace "aaa" "a" "b" 2)`, `"bba"`)
+	checkEval(t, `(string-repeat "ab" 3)`, `"ababab"`)
+	checkEvalError(t, `(string-repeat "ab" -1)`, "string-repeat - negative count -1")
+	checkEvalError(t, `(string-join (list "a" 1))`, "string-join - wrong argument type")
+}
+
+func TestCharacterPrimitives(t *testing.T) {
+	checkEval(t, `(string-ref "héllo" 1)`, `#\é`)
+	checkEval(t, `(string-length "héllo")`, `5`)
+	checkEval(t, `(string-substring "héllo" 1 3)`, `"él"`)
+	checkEval(t, `(string->list "hé")`, `(#\h #\é)`)
+	checkEval(t, `(list->string (list #\a #\space #\λ))`, `"a λ"`)
+	checkEval(t, `(integer->char 955)`, `#\λ`)
+	checkEval(t, `(list (char-letter? #\a) (char-digit? #\a) (char-space? #\space) (char-upper? #\A) (char-lower? #\A))`, `(#t #f #t #t #f)`)
+	checkEval(t, `(list (char-upcase #\é) (char-downcase #\A))`, `(#\É #\a)`)
+	checkEval(t, `(list (char? #\a) (char? "a"))`, `(#t #f)`)
+	checkEvalError(t, `(string-ref "abc" 3)`, "string-ref - index 3 out of bound")
+}
+
+func TestConversionPrimitives(t *testing.T) {
+	checkEval(t, `(+ (string->number "42") 1)`, `43`)
+	checkEval(t, `(string->number "ff" 16)`, `255`)
+	checkEval(t, `(string->number "abc")`, `#f`)
+	checkEval(t, `(number->string 255 2)`, `"11111111"`)
+	checkEval(t, `(number->string 42)`, `"42"`)
+	checkEval(t, `(string->symbol "abc")`, `abc`)
+	checkEval(t, `(symbol->string 'abc)`, `"abc"`)
+	checkEval(t, `(list (->string 42) (->string "a") (->string 'b))`, `("42" "a" "b")`)
+	checkEvalError(t, `(number->string 1 1)`, "number->string")
+}
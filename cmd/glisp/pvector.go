@@ -0,0 +1,185 @@
+package main
+
+// A persistent vector is a tree of nodes with up to 32 children, holding
+// elements in its leaves, plus a tail of up to 32 elements that have not
+// been pushed into the tree yet. Updates copy the path from the root to
+// the affected leaf and share everything else with the previous version.
+
+const PVEC_BITS = 5
+const PVEC_WIDTH = 1 << PVEC_BITS
+const PVEC_MASK = PVEC_WIDTH - 1
+
+type pvecNode struct {
+	children []*pvecNode // for inner nodes
+	items    []Value     // for leaves
+}
+
+type pvector struct {
+	count int
+	shift uint
+	root  *pvecNode
+	tail  []Value
+}
+
+func emptyPVector() *pvector {
+	return &pvector{0, PVEC_BITS, &pvecNode{}, []Value{}}
+}
+
+func pvectorFromSlice(vs []Value) *pvector {
+	result := emptyPVector()
+	for _, v := range vs {
+		result = result.conj(v)
+	}
+	return result
+}
+
+func (p *pvector) tailOffset() int {
+	if p.count < PVEC_WIDTH {
+		return 0
+	}
+	return ((p.count - 1) >> PVEC_BITS) << PVEC_BITS
+}
+
+func (p *pvector) leafFor(i int) []Value {
+	if i >= p.tailOffset() {
+		return p.tail
+	}
+	node := p.root
+	for level := p.shift; level > 0; level -= PVEC_BITS {
+		node = node.children[(i>>level)&PVEC_MASK]
+	}
+	return node.items
+}
+
+func (p *pvector) nth(i int) Value {
+	return p.leafFor(i)[i&PVEC_MASK]
+}
+
+func (p *pvector) toSlice() []Value {
+	result := make([]Value, 0, p.count)
+	for i := 0; i < p.count; i += PVEC_WIDTH {
+		result = append(result, p.leafFor(i)...)
+	}
+	return result
+}
+
+func (p *pvector) conj(v Value) *pvector {
+	if p.count-p.tailOffset() < PVEC_WIDTH {
+		tail := make([]Value, len(p.tail)+1)
+		copy(tail, p.tail)
+		tail[len(p.tail)] = v
+		return &pvector{p.count + 1, p.shift, p.root, tail}
+	}
+	// the tail is full: push it into the tree, growing a level if needed
+	tailNode := &pvecNode{items: p.tail}
+	shift := p.shift
+	var root *pvecNode
+	if (p.count >> PVEC_BITS) > (1 << p.shift) {
+		root = &pvecNode{children: []*pvecNode{p.root, newPVecPath(p.shift, tailNode)}}
+		shift += PVEC_BITS
+	} else {
+		root = p.pushTail(p.shift, p.root, tailNode)
+	}
+	return &pvector{p.count + 1, shift, root, []Value{v}}
+}
+
+func newPVecPath(level uint, node *pvecNode) *pvecNode {
+	if level == 0 {
+		return node
+	}
+	return &pvecNode{children: []*pvecNode{newPVecPath(level-PVEC_BITS, node)}}
+}
+
+func (p *pvector) pushTail(level uint, parent *pvecNode, tailNode *pvecNode) *pvecNode {
+	subidx := ((p.count - 1) >> level) & PVEC_MASK
+	children := make([]*pvecNode, len(parent.children), len(parent.children)+1)
+	copy(children, parent.children)
+	var insert *pvecNode
+	if level == PVEC_BITS {
+		insert = tailNode
+	} else if subidx < len(children) {
+		insert = p.pushTail(level-PVEC_BITS, children[subidx], tailNode)
+	} else {
+		insert = newPVecPath(level-PVEC_BITS, tailNode)
+	}
+	if subidx < len(children) {
+		children[subidx] = insert
+	} else {
+		children = append(children, insert)
+	}
+	return &pvecNode{children: children}
+}
+
+func (p *pvector) assocN(i int, v Value) *pvector {
+	// i must be in range, or equal to count to append
+	if i == p.count {
+		return p.conj(v)
+	}
+	if i >= p.tailOffset() {
+		tail := make([]Value, len(p.tail))
+		copy(tail, p.tail)
+		tail[i&PVEC_MASK] = v
+		return &pvector{p.count, p.shift, p.root, tail}
+	}
+	return &pvector{p.count, p.shift, assocPVecNode(p.shift, p.root, i, v), p.tail}
+}
+
+func assocPVecNode(level uint, node *pvecNode, i int, v Value) *pvecNode {
+	if level == 0 {
+		items := make([]Value, len(node.items))
+		copy(items, node.items)
+		items[i&PVEC_MASK] = v
+		return &pvecNode{items: items}
+	}
+	children := make([]*pvecNode, len(node.children))
+	copy(children, node.children)
+	subidx := (i >> level) & PVEC_MASK
+	children[subidx] = assocPVecNode(level-PVEC_BITS, children[subidx], i, v)
+	return &pvecNode{children: children}
+}
+
+func (p *pvector) pop() *pvector {
+	// p must not be empty
+	if p.count == 1 {
+		return emptyPVector()
+	}
+	if p.count-p.tailOffset() > 1 {
+		return &pvector{p.count - 1, p.shift, p.root, p.tail[:len(p.tail)-1]}
+	}
+	// the tail becomes the last leaf of the tree
+	tail := p.leafFor(p.count - 2)
+	root := p.popTail(p.shift, p.root)
+	shift := p.shift
+	if root == nil {
+		root = &pvecNode{}
+	}
+	if shift > PVEC_BITS && len(root.children) == 1 {
+		root = root.children[0]
+		shift -= PVEC_BITS
+	}
+	return &pvector{p.count - 1, shift, root, tail}
+}
+
+func (p *pvector) popTail(level uint, node *pvecNode) *pvecNode {
+	subidx := ((p.count - 2) >> level) & PVEC_MASK
+	if level > PVEC_BITS {
+		child := p.popTail(level-PVEC_BITS, node.children[subidx])
+		if child == nil && subidx == 0 {
+			return nil
+		}
+		children := make([]*pvecNode, subidx+1)
+		copy(children, node.children)
+		if child == nil {
+			children = children[:subidx]
+		} else {
+			children[subidx] = child
+		}
+		return &pvecNode{children: children}
+	}
+	if subidx == 0 {
+		return nil
+	}
+	children := make([]*pvecNode, subidx)
+	copy(children, node.children)
+	return &pvecNode{children: children}
+}
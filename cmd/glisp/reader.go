@@ -4,10 +4,25 @@ import "strconv"
 import "strings"
 import "regexp"
 import "errors"
+import "fmt"
+import "unicode/utf8"
+
+func skipSpace(s string) string {
+	// skip whitespace and ; comments up to the end of the line
+	ss := strings.TrimSpace(s)
+	for strings.HasPrefix(ss, ";") {
+		end := strings.Index(ss, "\n")
+		if end < 0 {
+			return ""
+		}
+		ss = strings.TrimSpace(ss[end:])
+	}
+	return ss
+}
 
 func readToken(token string, s string) (string, string) {
 	r, _ := regexp.Compile(`^` + token)
-	ss := strings.TrimSpace(s)
+	ss := skipSpace(s)
 	match := r.FindStringIndex(ss)
 	if len(match) == 0 {
 		// no match
@@ -19,7 +34,7 @@ func readToken(token string, s string) (string, string) {
 }
 
 func readChar(c byte, s string) (bool, string) {
-	ss := strings.TrimSpace(s)
+	ss := skipSpace(s)
 	if len(ss) > 0 && ss[0] == c {
 		return true, ss[1:]
 	}
@@ -45,11 +60,19 @@ func readQuote(s string) (bool, string) {
 
 func readSymbol(s string) (Value, string) {
 	//fmt.Println("Trying to read as symbol")
-	result, rest := readToken(`[^"'()#\s]+`, s)
+	result, rest := readToken(`[^"'()\[\]{}#;\s]+`, s)
+	if result == "" {
+		return nil, s
+	}
+	return intern(result), rest
+}
+
+func readKeyword(s string) (Value, string) {
+	result, rest := readToken(`:[^"'()\[\]{}#;\s]+`, s)
 	if result == "" {
 		return nil, s
 	}
-	return &vSymbol{result}, rest
+	return NewKeyword(result[1:]), rest
 }
 
 func readString(s string) (Value, string) {
@@ -61,6 +84,19 @@ func readString(s string) (Value, string) {
 	return &vString{result[1 : len(result)-1]}, rest
 }
 
+func readRegex(s string) (Value, string, error) {
+	// \" stands for " and other escapes are left to the regex syntax
+	result, rest := readToken(`#r"(?:[^\n"\\]|\\[^\n])*"`, s)
+	if result == "" {
+		return nil, s, nil
+	}
+	re, err := regexp.Compile(strings.ReplaceAll(result[3:len(result)-1], `\"`, `"`))
+	if err != nil {
+		return nil, s, err
+	}
+	return &vRegex{re}, rest, nil
+}
+
 func readInteger(s string) (Value, string) {
 	//fmt.Println("Trying to read as integer")
 	result, rest := readToken(`-?[0-9]+`, s)
@@ -71,6 +107,32 @@ func readInteger(s string) (Value, string) {
 	return &vInteger{num}, rest
 }
 
+func readCharacter(s string) (Value, string, error) {
+	result, rest := readToken(`#\\(?:[a-zA-Z0-9]+|.)`, s)
+	if result == "" {
+		return nil, s, nil
+	}
+	name := result[2:]
+	if utf8.RuneCountInString(name) == 1 {
+		r, _ := utf8.DecodeRuneInString(name)
+		return &vChar{r}, rest, nil
+	}
+	for r, n := range CHAR_NAMES {
+		if n == name {
+			return &vChar{r}, rest, nil
+		}
+	}
+	if name[0] == 'x' {
+		if code, err := strconv.ParseInt(name[1:], 16, 32); err == nil {
+			if !utf8.ValidRune(rune(code)) {
+				return nil, s, fmt.Errorf("invalid character code %s", name)
+			}
+			return &vChar{rune(code)}, rest, nil
+		}
+	}
+	return nil, s, fmt.Errorf("unknown character name %s", name)
+}
+
 func readBoolean(s string) (Value, string) {
 	// TODO: read all characters after # and then process
 	//       or treat # as a reader macro in some way?
@@ -90,6 +152,18 @@ func readList(s string) (Value, string, error) {
 	var result *vCons
 	expr, rest, err := read(s)
 	for err == nil {
+		if sym, ok := expr.(*vSymbol); ok && sym.name == "." {
+			// a dotted tail, as in (a b . c)
+			if current == nil {
+				return nil, s, errors.New("missing item before dot")
+			}
+			tail, r, err := read(rest)
+			if err != nil {
+				return nil, s, errors.New("missing item after dot")
+			}
+			current.tail = tail
+			return result, r, nil
+		}
 		if current == nil {
 			result = &vCons{head: expr, tail: &vEmpty{}}
 			current = result
@@ -106,6 +180,203 @@ func readList(s string) (Value, string, error) {
 	return result, rest, nil
 }
 
+func readVector(s string) (Value, string, error) {
+	// a vector is [item ...]
+	items, rest, err := readList(s)
+	if err != nil {
+		return nil, s, err
+	}
+	ok, rest := readChar(']', rest)
+	if !ok {
+		return nil, s, errors.New("missing closing bracket")
+	}
+	vs, err := listToValues(items)
+	if err != nil {
+		return nil, s, err
+	}
+	return &vVector{pvectorFromSlice(vs)}, rest, nil
+}
+
+// Hash-map and set literals are read as lists headed by these uninterned
+// symbols, so that their forms stay in source order until they are
+// evaluated; quote and the read primitive turn them into values.
+
+var mapLiteralTag = &vSymbol{name: "hash-map"}
+var setLiteralTag = &vSymbol{name: "set"}
+
+func collectionForm(v Value) (*vSymbol, []Value, bool) {
+	head, rest, ok := v.asCons()
+	if !ok || (head != mapLiteralTag && head != setLiteralTag) {
+		return nil, nil, false
+	}
+	items, _ := listToValues(rest)
+	return head.(*vSymbol), items, true
+}
+
+func literalValue(v Value) (Value, error) {
+	// v with the hash-map and set literals it contains turned into values
+	if tag, items, ok := collectionForm(v); ok {
+		for i, item := range items {
+			item, err := literalValue(item)
+			if err != nil {
+				return nil, err
+			}
+			items[i] = item
+		}
+		if tag == setLiteralTag {
+			s, err := setFromValues(items)
+			if err != nil {
+				return nil, err
+			}
+			return &vSet{s}, nil
+		}
+		m := emptyPMap()
+		for i := 0; i < len(items); i += 2 {
+			next, err := m.assoc(items[i], items[i+1])
+			if err != nil {
+				return nil, err
+			}
+			m = next
+		}
+		return &vMap{m}, nil
+	}
+	if p, ok := v.(*vVector); ok {
+		items := p.content.toSlice()
+		for i, item := range items {
+			item, err := literalValue(item)
+			if err != nil {
+				return nil, err
+			}
+			items[i] = item
+		}
+		return &vVector{pvectorFromSlice(items)}, nil
+	}
+	if head, tail, ok := v.asCons(); ok {
+		head, err := literalValue(head)
+		if err != nil {
+			return nil, err
+		}
+		tail, err = literalValue(tail)
+		if err != nil {
+			return nil, err
+		}
+		return &vCons{head: head, tail: tail}, nil
+	}
+	return v, nil
+}
+
+func readMap(s string) (Value, string, error) {
+	// a hash-map is {key value ...}
+	items, rest, err := readList(s)
+	if err != nil {
+		return nil, s, err
+	}
+	ok, rest := readChar('}', rest)
+	if !ok {
+		return nil, s, errors.New("missing closing brace")
+	}
+	vs, err := listToValues(items)
+	if err != nil {
+		return nil, s, err
+	}
+	if len(vs)%2 != 0 {
+		return nil, s, errors.New("odd number of forms in hash-map")
+	}
+	return &vCons{head: mapLiteralTag, tail: items}, rest, nil
+}
+
+func readSet(s string) (Value, string, error) {
+	// a set is #{item ...}
+	items, rest, err := readList(s)
+	if err != nil {
+		return nil, s, err
+	}
+	ok, rest := readChar('}', rest)
+	if !ok {
+		return nil, s, errors.New("missing closing brace")
+	}
+	if _, err := listToValues(items); err != nil {
+		return nil, s, err
+	}
+	return &vCons{head: setLiteralTag, tail: items}, rest, nil
+}
+
+func listToValues(v Value) ([]Value, error) {
+	result := []Value{}
+	current := v
+	for head, next, ok := v.asCons(); ok; head, next, ok = next.asCons() {
+		result = append(result, head)
+		current = next
+	}
+	if !current.isEmpty() {
+		return nil, errors.New("unexpected dot")
+	}
+	return result, nil
+}
+
+func formExtent(s string) (int, bool) {
+	// the end of the first form in s, or false when s ends before the
+	// form does; a stray or mismatched closing delimiter ends the form
+	const delimiters = " \t\r\n()[]{}\";"
+	closers := []byte{}
+	i := 0
+	for i < len(s) {
+		c := s[i]
+		switch {
+		case c == ';':
+			end := strings.Index(s[i:], "\n")
+			if end < 0 {
+				return 0, false
+			}
+			i += end
+			continue
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == '\'':
+			i++
+			continue
+		case c == '"' || strings.HasPrefix(s[i:], `#r"`):
+			// strings do not span lines, and regexes may escape "
+			regex := c == '#'
+			i = strings.Index(s[i:], `"`) + i + 1
+			for i < len(s) && s[i] != '"' && s[i] != '\n' {
+				if regex && s[i] == '\\' && i+1 < len(s) && s[i+1] != '\n' {
+					i++
+				}
+				i++
+			}
+			if i >= len(s) || s[i] != '"' {
+				return 0, false
+			}
+			i++
+		case strings.HasPrefix(s[i:], `#\`):
+			_, size := utf8.DecodeRuneInString(s[i+2:])
+			i += 2 + size
+			for i < len(s) && !strings.ContainsRune(delimiters, rune(s[i])) {
+				i++
+			}
+		case strings.HasPrefix(s[i:], "#{"):
+			closers = append(closers, '}')
+			i += 2
+		case c == '(' || c == '[' || c == '{':
+			closers = append(closers, map[byte]byte{'(': ')', '[': ']', '{': '}'}[c])
+			i++
+		case c == ')' || c == ']' || c == '}':
+			if len(closers) == 0 || closers[len(closers)-1] != c {
+				return i + 1, true
+			}
+			closers = closers[:len(closers)-1]
+			i++
+		default:
+			for i < len(s) && !strings.ContainsRune(delimiters, rune(s[i])) {
+				i++
+			}
+		}
+		if len(closers) == 0 {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
 func read(s string) (Value, string, error) {
 	//fmt.Println("Trying to read string", s)
 	var resultB bool
@@ -116,6 +387,10 @@ func read(s string) (Value, string, error) {
 	if result != nil {
 		return result, rest, nil
 	}
+	result, rest = readKeyword(s)
+	if result != nil {
+		return result, rest, nil
+	}
 	result, rest = readSymbol(s)
 	if result != nil {
 		return result, rest, nil
@@ -124,6 +399,17 @@ func read(s string) (Value, string, error) {
 	if result != nil {
 		return result, rest, nil
 	}
+	result, rest, err = readRegex(s)
+	if err != nil || result != nil {
+		return result, rest, err
+	}
+	if token, rest := readToken(`#\{`, s); token != "" {
+		return readSet(rest)
+	}
+	result, rest, err = readCharacter(s)
+	if err != nil || result != nil {
+		return result, rest, err
+	}
 	result, rest = readBoolean(s)
 	if result != nil {
 		return result, rest, nil
@@ -135,7 +421,7 @@ func read(s string) (Value, string, error) {
 		if err != nil {
 			return nil, s, err
 		}
-		return &vCons{head: &vSymbol{"quote"}, tail: &vCons{head: expr, tail: &vEmpty{}}}, rest, nil
+		return &vCons{head: NewSymbol("quote"), tail: &vCons{head: expr, tail: &vEmpty{}}}, rest, nil
 	}
 	resultB, rest = readLP(s)
 	if resultB {
@@ -153,6 +439,14 @@ func read(s string) (Value, string, error) {
 		}
 		return exprs, rest, nil
 	}
+	resultB, rest = readChar('[', s)
+	if resultB {
+		return readVector(rest)
+	}
+	resultB, rest = readChar('{', s)
+	if resultB {
+		return readMap(rest)
+	}
 	//return nil, s, nil
 	return nil, s, errors.New("Cannot read input")
 }
@@ -0,0 +1,33 @@
+package main
+
+import "testing"
+
+func TestCollectionLiterals(t *testing.T) {
+	checkEval(t, `(let ((vector list)) [1 2])`, `[1 2]`)
+	checkEval(t, `(let ((hash-map list)) (hash-map? {1 2}))`, `#t`)
+	checkEval(t, `(let ((set list)) (set? #{1 2}))`, `#t`)
+	// items are evaluated left to right, before the map is built
+	checkEval(t, `(def r (ref '())) (def m {1 (r (cons 1 (r))) 2 (r (cons 2 (r))) 3 (r (cons 3 (r)))}) (r)`, `(3 2 1)`)
+	checkEval(t, `(def r (ref 0)) (def m {"a" (r (+ (r) 1)) "a" (r (+ (r) 10))}) (r)`, `11`)
+	checkEval(t, `(get {"a" 1 "a" 2} "a")`, `2`)
+	checkEval(t, `(hash-map? '{a (1 2)})`, `#t`)
+	checkEval(t, `(get '{a {b 2}} 'a)`, `{b 2}`)
+	checkEval(t, `(set-member? (head '(#{1 2})) 2)`, `#t`)
+	checkEval(t, `(read (open-input-string "{a 1}"))`, `{a 1}`)
+}
+
+func TestRegexLiterals(t *testing.T) {
+	checkEval(t, `(regex-match? #r"^\"a\"$" (list->string (list #\" #\a #\")))`, `#t`)
+	checkEval(t, `(regex-match? #r"\d\\" "1\")`, `#t`)
+	checkEval(t, `#r"say \"hi\""`, `#r"say \"hi\""`)
+	checkEval(t, `(regex? #r"a")`, `#t`)
+	checkEval(t, `(keyword->string :a)`, `"a"`)
+	checkEval(t, `(port? (open-input-string "a"))`, `#t`)
+}
+
+func TestCharacterLiterals(t *testing.T) {
+	checkEval(t, `(char->integer #\x41)`, `65`)
+	checkEvalError(t, `#\xD800`, `invalid character code xD800`)
+	checkEvalError(t, `#\x110000`, `invalid character code`)
+	checkEvalError(t, `(integer->char 55296)`, `invalid code point`)
+}
@@ -0,0 +1,227 @@
+package main
+
+import "errors"
+import "fmt"
+
+// Record types are declared at top level, either as
+//
+//   (defrecord point (x y))
+//
+// which defines make-point, point?, point-x and point-y, or in the style
+// of SRFI 9, naming each procedure and optionally a setter per field:
+//
+//   (define-record-type point (make-point x y) point?
+//     (x point-x set-point-x!)
+//     (y point-y))
+//
+// Fields left out of the constructor start as #f.
+
+const kw_DEFRECORD string = "defrecord"
+const kw_DEFINERECORDTYPE string = "define-record-type"
+
+type astRecord struct {
+	name        string
+	fields      []string
+	constructor string
+	ctorFields  []string
+	predicate   string
+	accessors   []string
+	setters     []string // "" for a field without a setter
+}
+
+func parseRecord(sexp Value) (*astRecord, error) {
+	head, next, ok := sexp.asCons()
+	if !ok {
+		return nil, nil
+	}
+	if parseKeyword(kw_DEFRECORD, head) {
+		return parseDefrecord(next)
+	}
+	if parseKeyword(kw_DEFINERECORDTYPE, head) {
+		return parseDefineRecordType(next)
+	}
+	return nil, nil
+}
+
+func parseDefrecord(sexp Value) (*astRecord, error) {
+	head, next, ok := sexp.asCons()
+	if !ok {
+		return nil, errors.New("too few arguments to defrecord")
+	}
+	name, ok := head.asSymbol()
+	if !ok {
+		return nil, errors.New("record name not a symbol")
+	}
+	head, next, ok = next.asCons()
+	if !ok {
+		return nil, errors.New("too few arguments to defrecord")
+	}
+	if !next.isEmpty() {
+		return nil, errors.New("too many arguments to defrecord")
+	}
+	fields, err := parseNames(head)
+	if err != nil {
+		return nil, err
+	}
+	rec := &astRecord{
+		name:        name,
+		fields:      fields,
+		constructor: "make-" + name,
+		ctorFields:  fields,
+		predicate:   name + "?",
+		accessors:   make([]string, len(fields)),
+		setters:     make([]string, len(fields)),
+	}
+	for i, field := range fields {
+		rec.accessors[i] = name + "-" + field
+	}
+	return rec, checkRecordFields(rec)
+}
+
+func parseDefineRecordType(sexp Value) (*astRecord, error) {
+	head, next, ok := sexp.asCons()
+	if !ok {
+		return nil, errors.New("too few arguments to define-record-type")
+	}
+	name, ok := head.asSymbol()
+	if !ok {
+		return nil, errors.New("record name not a symbol")
+	}
+	head, next, ok = next.asCons()
+	if !ok {
+		return nil, errors.New("too few arguments to define-record-type")
+	}
+	ctor, err := parseNames(head)
+	if err != nil || len(ctor) == 0 {
+		return nil, errors.New("expected (constructor field ...) in define-record-type")
+	}
+	head, next, ok = next.asCons()
+	if !ok {
+		return nil, errors.New("too few arguments to define-record-type")
+	}
+	predicate, ok := head.asSymbol()
+	if !ok {
+		return nil, errors.New("record predicate not a symbol")
+	}
+	rec := &astRecord{
+		name:        name,
+		fields:      []string{},
+		constructor: ctor[0],
+		ctorFields:  ctor[1:],
+		predicate:   predicate,
+		accessors:   []string{},
+		setters:     []string{},
+	}
+	current := next
+	for head, next, ok := next.asCons(); ok; head, next, ok = next.asCons() {
+		spec, err := parseNames(head)
+		if err != nil || len(spec) < 2 || len(spec) > 3 {
+			return nil, errors.New("expected (field accessor [setter]) in define-record-type")
+		}
+		setter := ""
+		if len(spec) == 3 {
+			setter = spec[2]
+		}
+		rec.fields = append(rec.fields, spec[0])
+		rec.accessors = append(rec.accessors, spec[1])
+		rec.setters = append(rec.setters, setter)
+		current = next
+	}
+	if !current.isEmpty() {
+		return nil, errors.New("malformed define-record-type")
+	}
+	return rec, checkRecordFields(rec)
+}
+
+func checkRecordFields(rec *astRecord) error {
+	for i, field := range rec.fields {
+		for _, other := range rec.fields[:i] {
+			if field == other {
+				return fmt.Errorf("duplicate field %s in record %s", field, rec.name)
+			}
+		}
+	}
+	for _, field := range rec.ctorFields {
+		if recordFieldIndex(rec.fields, field) < 0 {
+			return fmt.Errorf("constructor field %s not a field of record %s", field, rec.name)
+		}
+	}
+	return nil
+}
+
+func recordFieldIndex(fields []string, field string) int {
+	for i, f := range fields {
+		if f == field {
+			return i
+		}
+	}
+	return -1
+}
+
+func recordArg(name string, rtype *recordType, arg Value) (*vRecord, error) {
+	r, ok := arg.(*vRecord)
+	if !ok || r.rtype != rtype {
+		return nil, fmt.Errorf("%s - expected record %s but got %s", name, rtype.name, arg.typ())
+	}
+	return r, nil
+}
+
+func recordPrimitives(rec *astRecord) []Primitive {
+	rtype := &recordType{rec.name, rec.fields}
+	prims := []Primitive{
+
+		Primitive{rec.constructor, len(rec.ctorFields), len(rec.ctorFields),
+			func(name string, args []Value) (Value, error) {
+				fields := make([]Value, len(rtype.fields))
+				for i := range fields {
+					fields[i] = NewBoolean(false)
+				}
+				for i, field := range rec.ctorFields {
+					fields[recordFieldIndex(rtype.fields, field)] = args[i]
+				}
+				return NewRecord(rtype, fields), nil
+			},
+		},
+
+		Primitive{rec.predicate, 1, 1,
+			func(name string, args []Value) (Value, error) {
+				r, ok := args[0].(*vRecord)
+				return NewBoolean(ok && r.rtype == rtype), nil
+			},
+		},
+	}
+	for i := range rec.fields {
+		i := i
+		prims = append(prims, Primitive{rec.accessors[i], 1, 1,
+			func(name string, args []Value) (Value, error) {
+				r, err := recordArg(name, rtype, args[0])
+				if err != nil {
+					return nil, err
+				}
+				return r.fields[i], nil
+			},
+		})
+		if rec.setters[i] == "" {
+			continue
+		}
+		prims = append(prims, Primitive{rec.setters[i], 2, 2,
+			func(name string, args []Value) (Value, error) {
+				r, err := recordArg(name, rtype, args[0])
+				if err != nil {
+					return nil, err
+				}
+				r.fields[i] = args[1]
+				return NewNil(), nil
+			},
+		})
+	}
+	return prims
+}
+
+func defineRecord(env *Env, rec *astRecord) {
+	// a new type each time, so instances of an earlier
+	// definition are not mistaken for the new one
+	for _, d := range recordPrimitives(rec) {
+		update(env, intern(d.name), NewPrimitive(d.name, MakePrimitive(d)))
+	}
+}
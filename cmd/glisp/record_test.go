@@ -0,0 +1,23 @@
+package main
+
+import "testing"
+
+func TestDefrecord(t *testing.T) {
+	checkEval(t, `(defrecord point (x y)) (def p (make-point 1 2)) (list (point? p) (point? 1) (point-x p) (point-y p))`, `(#t #f 1 2)`)
+	checkEval(t, `(defrecord point (x y)) (make-point 1 2)`, `#<point x=1 y=2>`)
+	checkEval(t, `(defrecord point (x y)) (equal? (make-point 1 2) (make-point 1 2))`, `#t`)
+	checkEvalError(t, `(defrecord point (x y)) (defrecord other (x)) (point-x (make-other 1))`, "point-x - expected record point but got")
+	checkEvalError(t, `(defrecord point (x x))`, "duplicate field x in record point")
+}
+
+func TestDefineRecordType(t *testing.T) {
+	src := `(define-record-type point (make-point x) point? (x point-x set-point-x!) (y point-y set-point-y!))`
+	checkEval(t, src+` (def p (make-point 1)) (list (point-x p) (point-y p))`, `(1 #f)`)
+	checkEval(t, src+` (def p (make-point 1)) (set-point-y! p 5) (point-y p)`, `5`)
+	checkEvalError(t, `(define-record-type point (make-point z) point? (x point-x))`, "constructor field z not a field of record point")
+	checkEvalError(t, `(define-record-type point (make-point x) point? (x))`, "expected (field accessor [setter]) in define-record-type")
+}
+
+func TestRedefinedRecordIsNewType(t *testing.T) {
+	checkEval(t, `(defrecord point (x)) (def p (make-point 1)) (defrecord point (x)) (point? p)`, `#f`)
+}
@@ -0,0 +1,278 @@
+package main
+
+import "fmt"
+
+// Sequences are lists, arrays, vectors, sets, strings (as characters),
+// dicts and hash-maps (as (key value) lists), iterables and lazy sequences. Primitives that work on any sequence
+// go through an iterator, which returns false once exhausted.
+
+type seqIterator func() (Value, bool, error)
+
+func isSequence(v Value) bool {
+	_, ok := iterate(v)
+	return ok
+}
+
+func sliceIterator(vs []Value) seqIterator {
+	i := 0
+	return func() (Value, bool, error) {
+		if i >= len(vs) {
+			return nil, false, nil
+		}
+		i += 1
+		return vs[i-1], true, nil
+	}
+}
+
+func iterate(v Value) (seqIterator, bool) {
+	if _, _, ok := v.asCons(); ok || v.isEmpty() {
+		current := v
+		return func() (Value, bool, error) {
+			head, next, ok := current.asCons()
+			if !ok {
+				if !current.isEmpty() {
+					return nil, false, fmt.Errorf("malformed list")
+				}
+				return nil, false, nil
+			}
+			current = next
+			return head, true, nil
+		}, true
+	}
+	if content, ok := v.asArray(); ok {
+		return sliceIterator(content), true
+	}
+	if str, ok := v.asString(); ok {
+		runes := []rune(str)
+		i := 0
+		return func() (Value, bool, error) {
+			if i >= len(runes) {
+				return nil, false, nil
+			}
+			i += 1
+			return NewChar(runes[i-1]), true, nil
+		}, true
+	}
+	if content, ok := v.asDict(); ok {
+		return sliceIterator(content.pairs()), true
+	}
+	if p, ok := v.(*vVector); ok {
+		i := 0
+		return func() (Value, bool, error) {
+			if i >= p.content.count {
+				return nil, false, nil
+			}
+			i += 1
+			return p.content.nth(i - 1), true, nil
+		}, true
+	}
+	if m, ok := v.(*vMap); ok {
+		return sliceIterator(m.content.pairs()), true
+	}
+	if s, ok := v.(*vSet); ok {
+		entries := s.content.entries()
+		items := make([]Value, len(entries))
+		for i, e := range entries {
+			items[i] = e.key
+		}
+		return sliceIterator(items), true
+	}
+	if l, ok := v.(*vLazySeq); ok {
+		return l.iterator(), true
+	}
+	if it, ok := v.(*vIterable); ok {
+		var next seqIterator
+		return func() (Value, bool, error) {
+			if next == nil {
+				n, err := it.start()
+				if err != nil {
+					return nil, false, err
+				}
+				next = n
+			}
+			return next()
+		}, true
+	}
+	return nil, false
+}
+
+func seqArg(name string, arg Value) (seqIterator, error) {
+	next, ok := iterate(arg)
+	if err := checkArgTypeB(name, arg, ok); err != nil {
+		return nil, err
+	}
+	return func() (Value, bool, error) {
+		v, ok, err := next()
+		if err != nil {
+			return nil, false, fmt.Errorf("%s - %s", name, err.Error())
+		}
+		return v, ok, nil
+	}, nil
+}
+
+func seqToSlice(name string, arg Value) ([]Value, error) {
+	if content, ok := arg.asArray(); ok {
+		result := make([]Value, len(content))
+		copy(result, content)
+		return result, nil
+	}
+	next, err := seqArg(name, arg)
+	if err != nil {
+		return nil, err
+	}
+	result := []Value{}
+	for {
+		v, ok, err := next()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return result, nil
+		}
+		result = append(result, v)
+	}
+}
+
+func seqLength(name string, arg Value) (int, error) {
+	if content, ok := arg.asArray(); ok {
+		return len(content), nil
+	}
+	if content, ok := arg.asDict(); ok {
+		return content.size(), nil
+	}
+	if str, ok := arg.asString(); ok {
+		return len([]rune(str)), nil
+	}
+	if p, ok := arg.(*vVector); ok {
+		return p.content.count, nil
+	}
+	if m, ok := arg.(*vMap); ok {
+		return m.content.count, nil
+	}
+	if s, ok := arg.(*vSet); ok {
+		return s.content.count, nil
+	}
+	items, err := seqToSlice(name, arg)
+	if err != nil {
+		return 0, err
+	}
+	return len(items), nil
+}
+
+func sameKind(model Value, items []Value) (Value, error) {
+	// rebuild a collection of the same kind as model from its elements;
+	// iterables give back lists
+	if _, ok := model.asArray(); ok {
+		return NewArray(items), nil
+	}
+	if _, ok := model.asString(); ok {
+		runes := make([]rune, len(items))
+		for i, item := range items {
+			r, ok := item.asChar()
+			if !ok {
+				return nil, fmt.Errorf("string element not a character %s", item.Display())
+			}
+			runes[i] = r
+		}
+		return NewString(string(runes)), nil
+	}
+	if _, ok := model.asDict(); ok {
+		content := newDictMap()
+		for _, item := range items {
+			key, rest, _ := item.asCons()
+			v, _, _ := rest.asCons()
+			if err := content.set(key, v); err != nil {
+				return nil, err
+			}
+		}
+		return NewDict(content), nil
+	}
+	if _, ok := model.(*vVector); ok {
+		return NewVector(items), nil
+	}
+	if _, ok := model.(*vMap); ok {
+		m := emptyPMap()
+		for _, item := range items {
+			key, rest, _ := item.asCons()
+			v, _, _ := rest.asCons()
+			next, err := m.assoc(key, v)
+			if err != nil {
+				return nil, err
+			}
+			m = next
+		}
+		return NewMap(m), nil
+	}
+	if _, ok := model.(*vSet); ok {
+		s, err := setFromValues(items)
+		if err != nil {
+			return nil, err
+		}
+		return NewSet(s), nil
+	}
+	return listFromSlice(items), nil
+}
+
+func iterableFromFunction(name string, f Value) (seqIterator, error) {
+	// f returns a generator, called repeatedly until it returns eof
+	gen, err := applyFirst(f, []Value{})
+	if err != nil {
+		return nil, err
+	}
+	if !gen.isFunction() {
+		return nil, fmt.Errorf("%s - iterable function did not return a function", name)
+	}
+	done := false
+	return func() (Value, bool, error) {
+		if done {
+			return nil, false, nil
+		}
+		v, err := applyFirst(gen, []Value{})
+		if err != nil {
+			return nil, false, err
+		}
+		if _, ok := v.(*vEOF); ok {
+			done = true
+			return nil, false, nil
+		}
+		return v, true, nil
+	}, nil
+}
+
+func rangeIterable(start int, end int, step int) Value {
+	return NewIterable(fmt.Sprintf("range %d %d %d", start, end, step), func() (seqIterator, error) {
+		i := start
+		return func() (Value, bool, error) {
+			if (step > 0 && i >= end) || (step < 0 && i <= end) {
+				return nil, false, nil
+			}
+			i += step
+			return NewInteger(i - step), true, nil
+		}, nil
+	})
+}
+
+func seqArgs(name string, args []Value) ([]seqIterator, error) {
+	nexts := make([]seqIterator, len(args))
+	for i, arg := range args {
+		next, err := seqArg(name, arg)
+		if err != nil {
+			return nil, err
+		}
+		nexts[i] = next
+	}
+	return nexts, nil
+}
+
+func nextAll(nexts []seqIterator) ([]Value, bool, error) {
+	// advance sequences in lockstep, stopping with the shortest one
+	firsts := make([]Value, len(nexts))
+	for i, next := range nexts {
+		v, ok, err := next()
+		if err != nil || !ok {
+			return nil, false, err
+		}
+		firsts[i] = v
+	}
+	return firsts, true, nil
+}
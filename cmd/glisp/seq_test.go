@@ -0,0 +1,33 @@
+package main
+
+import "testing"
+
+func TestSequencePrimitives(t *testing.T) {
+	checkEval(t, `(map (fn (x) (* x 2)) (array 1 2))`, `#[2 4]`)
+	checkEval(t, `(filter (fn (c) (not (= c #\b))) "abc")`, `"ac"`)
+	checkEval(t, `(foldl + (in-range 0 5) 0)`, `10`)
+	checkEval(t, `(list (length "abc") (length (array 1 2)) (nth "abc" 1))`, `(3 2 #\b)`)
+	checkEval(t, `(map (fn (k v) (list k v)) (dict '(a 1)) (array 1))`, `(((a 1) 1))`)
+}
+
+func TestTakeAndDropOnSequences(t *testing.T) {
+	checkEval(t, `(take 2 (in-range 0 10))`, `(0 1)`)
+	checkEval(t, `(take 2 (array 1 2 3))`, `#[1 2]`)
+	checkEval(t, `(take 1 "abc")`, `"a"`)
+	checkEval(t, `(drop 2 (array 1 2 3))`, `#[3]`)
+	checkEval(t, `(drop 1 "abc")`, `"bc"`)
+	checkEval(t, `(drop 2 (in-range 0 4))`, `(2 3)`)
+}
+
+func TestListPrimitivesOnSequences(t *testing.T) {
+	checkEval(t, `(reverse "abc")`, `"cba"`)
+	checkEval(t, `(sort (array 3 1 2))`, `#[1 2 3]`)
+	checkEval(t, `(zip (array 1 2 3) "ab")`, `((1 #\a) (2 #\b))`)
+	checkEval(t, `(last "xyz")`, `#\z`)
+	checkEval(t, `(any (fn (x) (> x 2)) (in-range 0 1000000000))`, `#t`)
+	checkEval(t, `(find (fn (c) (= c #\b)) "abc")`, `#\b`)
+	checkEval(t, `(remove (fn (x) (= x 1)) (array 1 2 1))`, `#[2]`)
+	checkEval(t, `(distinct "abca")`, `"abc"`)
+	checkEval(t, `(partition (fn (x) (> x 1)) (array 1 2 3))`, `((2 3) (1))`)
+	checkEval(t, `(string-join (array "a" "b") "-")`, `"a-b"`)
+}
@@ -0,0 +1,106 @@
+package main
+
+import "fmt"
+import "os"
+import "path/filepath"
+import "sort"
+import "strings"
+
+// Per-engine state that primitives close over, so that several engines
+// embedded in the same process do not interfere with each other.
+
+type engineState struct {
+	dir     string            // current directory, always absolute
+	environ map[string]string // environment passed to commands
+	stdin   *vPort
+	stdout  *vPort
+	stderr  *vPort
+	modules map[string]*module // loaded modules, by name
+}
+
+func newEngineState() *engineState {
+	dir, err := os.Getwd()
+	if err != nil {
+		dir = "/"
+	}
+	environ := map[string]string{}
+	for _, kv := range os.Environ() {
+		if i := strings.Index(kv, "="); i > 0 {
+			environ[kv[:i]] = kv[i+1:]
+		}
+	}
+	return &engineState{
+		dir:     dir,
+		environ: environ,
+		stdin:   NewInputPort("stdin", os.Stdin, nil),
+		stdout:  NewOutputPort("stdout", os.Stdout, nil),
+		stderr:  NewOutputPort("stderr", os.Stderr, nil),
+		modules: map[string]*module{},
+	}
+}
+
+func (st *engineState) environList() []string {
+	// environment in the NAME=value form expected by os/exec
+	result := make([]string, 0, len(st.environ))
+	for k, v := range st.environ {
+		result = append(result, k+"="+v)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func isHomePath(path string) bool {
+	return path == "~" || strings.HasPrefix(path, "~/")
+}
+
+func (st *engineState) expandHome(path string) (string, error) {
+	if !isHomePath(path) {
+		return path, nil
+	}
+	home, ok := st.environ["HOME"]
+	if !ok {
+		dir, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		home = dir
+	}
+	return filepath.Join(home, path[1:]), nil
+}
+
+func (st *engineState) resolve(path string) string {
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path)
+	}
+	return filepath.Join(st.dir, path)
+}
+
+func (st *engineState) pathArg(name string, args []Value, i int) (string, error) {
+	// path argument resolved against the current directory
+	path, err := pathArg(name, args, i)
+	if err != nil {
+		return "", err
+	}
+	expanded, err := st.expandHome(path)
+	if err != nil {
+		return "", fsError(name, err)
+	}
+	return st.resolve(expanded), nil
+}
+
+func (st *engineState) chdir(path string) error {
+	expanded, err := st.expandHome(path)
+	if err != nil {
+		return err
+	}
+	dir := st.resolve(expanded)
+	info, err := os.Stat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+	st.dir = dir
+	return nil
+}
@@ -0,0 +1,8 @@
+//go:build !linux && !darwin
+// +build !linux,!darwin
+
+package main
+
+func ttyWidth() int {
+	return 0
+}
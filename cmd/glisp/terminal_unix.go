@@ -0,0 +1,18 @@
+//go:build linux || darwin
+// +build linux darwin
+
+package main
+
+import "os"
+import "syscall"
+import "unsafe"
+
+func ttyWidth() int {
+	// the width of the terminal on stdout, or 0 when it is not a terminal
+	var ws struct{ rows, cols, xpixel, ypixel uint16 }
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, os.Stdout.Fd(), uintptr(syscall.TIOCGWINSZ), uintptr(unsafe.Pointer(&ws)))
+	if errno != 0 {
+		return 0
+	}
+	return int(ws.cols)
+}
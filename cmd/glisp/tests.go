@@ -38,13 +38,13 @@ func primitiveMult(args []Value) (Value, error) {
 }
 
 func sampleEnv() *Env {
-	current := map[string]Value{
-		"a": &vInteger{10},
-		"b": &vInteger{20},
-		"+": &vPrimitive{"+", primitiveAdd},
-		"*": &vPrimitive{"*", primitiveMult},
-		"t": &vBoolean{true},
-		"f": &vBoolean{false},
+	current := map[*vSymbol]Value{
+		intern("a"): &vInteger{10},
+		intern("b"): &vInteger{20},
+		intern("+"): &vPrimitive{"+", primitiveAdd},
+		intern("*"): &vPrimitive{"*", primitiveMult},
+		intern("t"): &vBoolean{true},
+		intern("f"): &vBoolean{false},
 	}
 	env := &Env{bindings: current}
 	return env
@@ -81,26 +81,26 @@ func test_literal() {
 
 func test_lookup() {
 	env := sampleEnv()
-	e1 := &astId{"a"}
+	e1 := &astId{intern("a")}
 	fmt.Println(e1.str(), "->", evalDisplay(e1, env))
-	e2 := &astId{"+"}
+	e2 := &astId{intern("+")}
 	fmt.Println(e2.str(), "->", evalDisplay(e2, env))
 }
 
 func test_apply() {
 	env := sampleEnv()
-	e1 := &astId{"a"}
-	e2 := &astId{"b"}
+	e1 := &astId{intern("a")}
+	e2 := &astId{intern("b")}
 	args := []ast{e1, e2}
-	e3 := &astApply{&astId{"+"}, args}
+	e3 := &astApply{&astId{intern("+")}, args}
 	fmt.Println(e3.str(), "->", evalDisplay(e3, env))
 }
 
 func test_if() {
 	env := sampleEnv()
-	e1 := &astIf{&astId{"t"}, &astId{"a"}, &astId{"b"}}
+	e1 := &astIf{&astId{intern("t")}, &astId{intern("a")}, &astId{intern("b")}}
 	fmt.Println(e1.str(), "->", evalDisplay(e1, env))
-	e2 := &astIf{&astId{"f"}, &astId{"a"}, &astId{"b"}}
+	e2 := &astIf{&astId{intern("f")}, &astId{intern("a")}, &astId{intern("b")}}
 	fmt.Println(e2.str(), "->", evalDisplay(e2, env))
 }
 
@@ -95,18 +95,7 @@ func (v *vArray) isNil() bool {
 }
 
 func (v *vArray) isEqual(vv Value) bool {
-	return v == vv // pointer equality because arrays will be mutable
-	/*
-		if !vv.isArray() || len(v.content) != len(vv.getArray()) {
-			return false}
-		vvcontent := vv.getArray()
-		for i := range(v.content) {
-			if !v.content[i].isEqual(vvcontent[i]) {
-				return false
-			}
-		}
-		return true
-	*/
+	return equalValues(v, vv)
 }
 
 func (v *vArray) typ() string {
@@ -145,6 +134,22 @@ func (v *vArray) asArray() ([]Value, bool) {
 	return v.content, true
 }
 
-func (v *vArray) asDict() (map[string]Value, bool) {
+func (v *vArray) asDict() (*dictMap, bool) {
+	return nil, false
+}
+
+func (v *vArray) asChar() (rune, bool) {
+	return 0, false
+}
+
+func (v *vArray) asKeyword() (string, bool) {
+	return "", false
+}
+
+func (v *vArray) asRegex() (*vRegex, bool) {
+	return nil, false
+}
+
+func (v *vArray) asPort() (*vPort, bool) {
 	return nil, false
 }
@@ -117,6 +117,22 @@ func (v *vBoolean) asArray() ([]Value, bool) {
 	return nil, false
 }
 
-func (v *vBoolean) asDict() (map[string]Value, bool) {
+func (v *vBoolean) asDict() (*dictMap, bool) {
+	return nil, false
+}
+
+func (v *vBoolean) asChar() (rune, bool) {
+	return 0, false
+}
+
+func (v *vBoolean) asKeyword() (string, bool) {
+	return "", false
+}
+
+func (v *vBoolean) asRegex() (*vRegex, bool) {
+	return nil, false
+}
+
+func (v *vBoolean) asPort() (*vPort, bool) {
 	return nil, false
 }
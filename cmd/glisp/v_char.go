@@ -0,0 +1,142 @@
+package main
+
+import (
+	"fmt"
+)
+
+type vChar struct {
+	val rune
+}
+
+func NewChar(v rune) Value {
+	return &vChar{v}
+}
+
+var CHAR_NAMES = map[rune]string{
+	' ':    "space",
+	'\n':   "newline",
+	'\t':   "tab",
+	'\r':   "return",
+	0:      "nul",
+	'\x1b': "escape",
+}
+
+func (v *vChar) Display() string {
+	if name, ok := CHAR_NAMES[v.val]; ok {
+		return "#\\" + name
+	}
+	return "#\\" + string(v.val)
+}
+
+func (v *vChar) DisplayCDR() string {
+	panic(fmt.Sprintf("unchecked access to %s", v.str()))
+}
+
+func (v *vChar) apply(args []Value) (Value, error) {
+	return nil, fmt.Errorf("Value %s not applicable", v.str())
+}
+
+func (v *vChar) str() string {
+	return fmt.Sprintf("VChar[%q]", v.val)
+}
+
+func (v *vChar) isAtom() bool {
+	return true
+}
+
+func (v *vChar) isSymbol() bool {
+	return false
+}
+
+func (v *vChar) isCons() bool {
+	return false
+}
+
+func (v *vChar) isEmpty() bool {
+	return false
+}
+
+func (v *vChar) isNumber() bool {
+	return false
+}
+
+func (v *vChar) isBool() bool {
+	return false
+}
+
+func (v *vChar) isString() bool {
+	return false
+}
+
+func (v *vChar) isFunction() bool {
+	return false
+}
+
+func (v *vChar) isTrue() bool {
+	return true
+}
+
+func (v *vChar) isNil() bool {
+	return false
+}
+
+func (v *vChar) isEqual(vv Value) bool {
+	c, ok := vv.asChar()
+	return ok && v.val == c
+}
+
+func (v *vChar) typ() string {
+	return "char"
+}
+
+func (v *vChar) asInteger() (int, bool) {
+	return 0, false
+}
+
+func (v *vChar) asBoolean() (bool, bool) {
+	return false, false
+}
+
+func (v *vChar) asString() (string, bool) {
+	return "", false
+}
+
+func (v *vChar) asSymbol() (string, bool) {
+	return "", false
+}
+
+func (v *vChar) asCons() (Value, Value, bool) {
+	return nil, nil, false
+}
+
+func (v *vChar) asReference() (Value, func(Value), bool) {
+	return nil, nil, false
+}
+
+func (v *vChar) setReference(Value) bool {
+	return false
+}
+
+func (v *vChar) asArray() ([]Value, bool) {
+	return nil, false
+}
+
+func (v *vChar) asDict() (*dictMap, bool) {
+	return nil, false
+}
+
+func (v *vChar) asChar() (rune, bool) {
+	return v.val, true
+}
+
+func (v *vChar) asKeyword() (string, bool) {
+	return "", false
+}
+
+func (v *vChar) asRegex() (*vRegex, bool) {
+	return nil, false
+}
+
+func (v *vChar) asPort() (*vPort, bool) {
+	return nil, false
+}
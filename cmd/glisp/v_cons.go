@@ -25,11 +25,19 @@ func (v MutableCons) setTail(tail Value) {
 }
 
 func (v *vCons) Display() string {
-	return "(" + v.head.Display() + v.tail.DisplayCDR()
+	return "(" + v.head.Display() + displayTail(v.tail)
 }
 
 func (v *vCons) DisplayCDR() string {
-	return " " + v.head.Display() + v.tail.DisplayCDR()
+	return " " + v.head.Display() + displayTail(v.tail)
+}
+
+func displayTail(tail Value) string {
+	// a tail that is not a list is shown after a dot
+	if _, _, ok := tail.asCons(); ok || tail.isEmpty() {
+		return tail.DisplayCDR()
+	}
+	return " . " + tail.Display() + ")"
 }
 
 func (v *vCons) apply(args []Value) (Value, error) {
@@ -81,23 +89,7 @@ func (v *vCons) isNil() bool {
 }
 
 func (v *vCons) isEqual(vv Value) bool {
-	if _, _, ok := vv.asCons(); !ok {
-		return false
-	}
-	var curr1 Value = v
-	var curr2 Value = vv
-	for head1, tail1, ok := v.asCons(); ok; head1, tail1, ok = tail1.asCons() {
-		head2, tail2, ok := curr2.asCons()
-		if !ok {
-			return false
-		}
-		if !head1.isEqual(head2) {
-			return false
-		}
-		curr1 = tail1
-		curr2 = tail2
-	}
-	return curr1.isEqual(curr2) // should both be empty at the end
+	return equalValues(v, vv)
 }
 
 func (v *vCons) typ() string {
@@ -136,6 +128,22 @@ func (v *vCons) asArray() ([]Value, bool) {
 	return nil, false
 }
 
-func (v *vCons) asDict() (map[string]Value, bool) {
+func (v *vCons) asDict() (*dictMap, bool) {
+	return nil, false
+}
+
+func (v *vCons) asChar() (rune, bool) {
+	return 0, false
+}
+
+func (v *vCons) asKeyword() (string, bool) {
+	return "", false
+}
+
+func (v *vCons) asRegex() (*vRegex, bool) {
+	return nil, false
+}
+
+func (v *vCons) asPort() (*vPort, bool) {
 	return nil, false
 }
@@ -0,0 +1,26 @@
+package main
+
+import "testing"
+
+func TestDottedPairs(t *testing.T) {
+	checkEval(t, `(cons 1 2)`, `(1 . 2)`)
+	checkEval(t, `'(1 2 . 3)`, `(1 2 . 3)`)
+	checkEval(t, `(cdr (cdr '(1 2 . 3)))`, `3`)
+	checkEval(t, `(list (pair? (cons 1 2)) (list? (cons 1 2)) (list? '(1 2)))`, `(#t #f #t)`)
+	checkEvalError(t, `(car '())`, `car`)
+}
+
+func TestImproperListsInPrimitives(t *testing.T) {
+	checkEval(t, `(append '(1) '(2 . 3))`, `(1 2 . 3)`)
+	checkEvalError(t, `(append '(1 . 2) '(3))`, `append - malformed list`)
+	checkEval(t, `(flatten '(1 (2 . 3) ((4))))`, `(1 2 3 4)`)
+}
+
+func TestReadDottedPairs(t *testing.T) {
+	checkEvalError(t, `'( . 1)`, "missing item before dot")
+	checkEval(t, `(length (dict-keys (dict (list '(1 . 2) 'a) (list '(1 2) 'b))))`, `2`)
+	checkEval(t, `(equal? '(1 . 2) (cons 1 2))`, `#t`)
+	if pretty := prettyDisplay(NewCons(NewInteger(1), NewInteger(2)), 80); pretty != "(1 . 2)" {
+		t.Errorf("expected (1 . 2) but got %s", pretty)
+	}
+}
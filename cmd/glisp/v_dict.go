@@ -6,19 +6,18 @@ import (
 )
 
 type vDict struct {
-	content map[string]Value
+	content *dictMap
 }
 
-func NewDict(vs map[string]Value) Value {
+func NewDict(vs *dictMap) Value {
 	return &vDict{vs}
 }
 
 func (v *vDict) Display() string {
-	s := make([]string, len(v.content))
-	i := 0
-	for k, vv := range v.content {
-		s[i] = fmt.Sprintf("(%s %s)", k, vv.Display())
-		i++
+	entries := v.content.entries()
+	s := make([]string, len(entries))
+	for i, entry := range entries {
+		s[i] = fmt.Sprintf("(%s %s)", entry.key.Display(), entry.value.Display())
 	}
 	return fmt.Sprintf("#(%s)", strings.Join(s, " "))
 }
@@ -34,27 +33,27 @@ func (v *vDict) apply(args []Value) (Value, error) {
 	if len(args) > 2 {
 		return nil, fmt.Errorf("too many arguments %d to dict update", len(args))
 	}
-	key, ok := args[0].asSymbol()
-	if !ok { 
-		return nil, fmt.Errorf("dict indexing requires a symbol key")
-	}
 	if len(args) == 2 {
-		v.content[key] = args[1]
+		if err := v.content.set(args[0], args[1]); err != nil {
+			return nil, err
+		}
 		return &vNil{}, nil
 	}
-	result, ok := v.content[key]
+	result, ok, err := v.content.get(args[0])
+	if err != nil {
+		return nil, err
+	}
 	if !ok {
-		return nil, fmt.Errorf("key %s not in dict", key)
+		return nil, fmt.Errorf("key %s not in dict", args[0].Display())
 	}
 	return result, nil
 }
 
 func (v *vDict) str() string {
-	s := make([]string, len(v.content))
-	i := 0
-	for k, vv := range v.content {
-		s[i] = fmt.Sprintf("[%s %s]", k, vv.str())
-		i++
+	entries := v.content.entries()
+	s := make([]string, len(entries))
+	for i, entry := range entries {
+		s[i] = fmt.Sprintf("[%s %s]", entry.key.str(), entry.value.str())
 	}
 	return fmt.Sprintf("VDict[%s]", strings.Join(s, " "))
 }
@@ -100,7 +99,7 @@ func (v *vDict) isNil() bool {
 }
 
 func (v *vDict) isEqual(vv Value) bool {
-	return v == vv // pointer equality due to mutability
+	return equalValues(v, vv)
 }
 
 func (v *vDict) typ() string {
@@ -139,6 +138,22 @@ func (v *vDict) asArray() ([]Value, bool) {
 	return nil, false
 }
 
-func (v *vDict) asDict() (map[string]Value, bool) {
+func (v *vDict) asDict() (*dictMap, bool) {
 	return v.content, true
 }
+
+func (v *vDict) asChar() (rune, bool) {
+	return 0, false
+}
+
+func (v *vDict) asKeyword() (string, bool) {
+	return "", false
+}
+
+func (v *vDict) asRegex() (*vRegex, bool) {
+	return nil, false
+}
+
+func (v *vDict) asPort() (*vPort, bool) {
+	return nil, false
+}
@@ -107,6 +107,22 @@ func (v *vEmpty) asArray() ([]Value, bool) {
 	return nil, false
 }
 
-func (v *vEmpty) asDict() (map[string]Value, bool) {
+func (v *vEmpty) asDict() (*dictMap, bool) {
+	return nil, false
+}
+
+func (v *vEmpty) asChar() (rune, bool) {
+	return 0, false
+}
+
+func (v *vEmpty) asKeyword() (string, bool) {
+	return "", false
+}
+
+func (v *vEmpty) asRegex() (*vRegex, bool) {
+	return nil, false
+}
+
+func (v *vEmpty) asPort() (*vPort, bool) {
 	return nil, false
 }
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+)
+
+// The end of file object returned by input primitives once
+// a port is exhausted.
+
+type vEOF struct {
+}
+
+func NewEOF() Value {
+	return &vEOF{}
+}
+
+func (v *vEOF) Display() string {
+	return "#<eof>"
+}
+
+func (v *vEOF) DisplayCDR() string {
+	panic(fmt.Sprintf("unchecked access to %s", v.str()))
+}
+
+func (v *vEOF) apply(args []Value) (Value, error) {
+	return nil, fmt.Errorf("Value %s not applicable", v.str())
+}
+
+func (v *vEOF) str() string {
+	return fmt.Sprintf("VEOF")
+}
+
+func (v *vEOF) isAtom() bool {
+	return true
+}
+
+func (v *vEOF) isSymbol() bool {
+	return false
+}
+
+func (v *vEOF) isCons() bool {
+	return false
+}
+
+func (v *vEOF) isEmpty() bool {
+	return false
+}
+
+func (v *vEOF) isNumber() bool {
+	return false
+}
+
+func (v *vEOF) isBool() bool {
+	return false
+}
+
+func (v *vEOF) isString() bool {
+	return false
+}
+
+func (v *vEOF) isFunction() bool {
+	return false
+}
+
+func (v *vEOF) isTrue() bool {
+	return true
+}
+
+func (v *vEOF) isNil() bool {
+	return false
+}
+
+func (v *vEOF) isEqual(vv Value) bool {
+	_, ok := vv.(*vEOF)
+	return ok
+}
+
+func (v *vEOF) typ() string {
+	return "eof"
+}
+
+func (v *vEOF) asInteger() (int, bool) {
+	return 0, false
+}
+
+func (v *vEOF) asBoolean() (bool, bool) {
+	return false, false
+}
+
+func (v *vEOF) asString() (string, bool) {
+	return "", false
+}
+
+func (v *vEOF) asSymbol() (string, bool) {
+	return "", false
+}
+
+func (v *vEOF) asCons() (Value, Value, bool) {
+	return nil, nil, false
+}
+
+func (v *vEOF) asReference() (Value, func(Value), bool) {
+	return nil, nil, false
+}
+
+func (v *vEOF) setReference(Value) bool {
+	return false
+}
+
+func (v *vEOF) asArray() ([]Value, bool) {
+	return nil, false
+}
+
+func (v *vEOF) asDict() (*dictMap, bool) {
+	return nil, false
+}
+
+func (v *vEOF) asChar() (rune, bool) {
+	return 0, false
+}
+
+func (v *vEOF) asKeyword() (string, bool) {
+	return "", false
+}
+
+func (v *vEOF) asRegex() (*vRegex, bool) {
+	return nil, false
+}
+
+func (v *vEOF) asPort() (*vPort, bool) {
+	return nil, false
+}
@@ -5,18 +5,26 @@ import (
 	"strings"
 )
 
+// Keyword parameters follow &key in a parameter list. They are passed
+// after the positional arguments as :name value pairs, in any order, and
+// the ones not passed are #nil:
+//
+//   (def (connect host &key port timeout) ...)
+//   (connect "localhost" :timeout 10)
+
 type vFunction struct {
-	params []string
+	params []*vSymbol
+	keys   []*vSymbol
 	body   ast
 	env    *Env
 }
 
-func NewFunction(params []string, body ast, env *Env) Value {
-	return &vFunction{params, body, env}
+func NewFunction(params []*vSymbol, keys []*vSymbol, body ast, env *Env) Value {
+	return &vFunction{params, keys, body, env}
 }
 
 func (v *vFunction) Display() string {
-	return fmt.Sprintf("#<fun %s ...>", strings.Join(v.params, " "))
+	return fmt.Sprintf("#<fun %s ...>", paramsString(v.params, v.keys))
 }
 
 func (v *vFunction) DisplayCDR() string {
@@ -24,15 +32,63 @@ func (v *vFunction) DisplayCDR() string {
 }
 
 func (v *vFunction) apply(args []Value) (Value, error) {
-	if len(v.params) != len(args) {
-		return nil, fmt.Errorf("Wrong number of arguments to application to %s", v.str())
+	newEnv, err := v.bind(args)
+	if err != nil {
+		return nil, err
 	}
-	newEnv := layer(v.env, v.params, args)
 	return v.body.eval(newEnv)
 }
 
+func (v *vFunction) bind(args []Value) (*Env, error) {
+	// the environment in which the body sees its arguments
+	if len(args) < len(v.params) || (len(v.keys) == 0 && len(args) > len(v.params)) {
+		return nil, fmt.Errorf("Wrong number of arguments to application to %s", v.str())
+	}
+	if len(v.keys) == 0 {
+		return layer(v.env, v.params, args), nil
+	}
+	names := append(append([]*vSymbol{}, v.params...), v.keys...)
+	values := append([]Value{}, args[:len(v.params)]...)
+	for range v.keys {
+		values = append(values, &vNil{})
+	}
+	rest := args[len(v.params):]
+	if len(rest)%2 != 0 {
+		return nil, fmt.Errorf("Keyword argument without a value in application to %s", v.str())
+	}
+	for i := 0; i < len(rest); i += 2 {
+		k, ok := rest[i].asKeyword()
+		if !ok {
+			return nil, fmt.Errorf("Expected keyword argument but got %s in application to %s", rest[i].Display(), v.str())
+		}
+		j := keyIndex(v.keys, k)
+		if j < 0 {
+			return nil, fmt.Errorf("Unknown keyword argument :%s in application to %s", k, v.str())
+		}
+		values[len(v.params)+j] = rest[i+1]
+	}
+	return layer(v.env, names, values), nil
+}
+
+func keyIndex(keys []*vSymbol, name string) int {
+	for i, key := range keys {
+		if key.name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func paramsString(params []*vSymbol, keys []*vSymbol) string {
+	names := symbolNames(params)
+	if len(keys) > 0 {
+		names = append(append(names, kw_KEY), symbolNames(keys)...)
+	}
+	return strings.Join(names, " ")
+}
+
 func (v *vFunction) str() string {
-	return fmt.Sprintf("VFunction[[%s] %s]", strings.Join(v.params, " "), v.body.str())
+	return fmt.Sprintf("VFunction[[%s] %s]", paramsString(v.params, v.keys), v.body.str())
 }
 
 func (v *vFunction) isAtom() bool {
@@ -115,6 +171,22 @@ func (v *vFunction) asArray() ([]Value, bool) {
 	return nil, false
 }
 
-func (v *vFunction) asDict() (map[string]Value, bool) {
+func (v *vFunction) asDict() (*dictMap, bool) {
+	return nil, false
+}
+
+func (v *vFunction) asChar() (rune, bool) {
+	return 0, false
+}
+
+func (v *vFunction) asKeyword() (string, bool) {
+	return "", false
+}
+
+func (v *vFunction) asRegex() (*vRegex, bool) {
+	return nil, false
+}
+
+func (v *vFunction) asPort() (*vPort, bool) {
 	return nil, false
 }
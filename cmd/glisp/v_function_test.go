@@ -0,0 +1,19 @@
+package main
+
+import "testing"
+
+func TestKeywordParameters(t *testing.T) {
+	checkEval(t, `(def (connect host &key port timeout) (list host port timeout)) (connect "h" :timeout 10)`, `("h" #nil 10)`)
+	checkEval(t, `(def (connect host &key port timeout) (list host port timeout)) (connect "h" :timeout 2 :port 1)`, `("h" 1 2)`)
+	checkEval(t, `(def (connect host &key port timeout) (list host port timeout)) (connect "h")`, `("h" #nil #nil)`)
+	checkEval(t, `((fn (&key a) a) :a 5)`, `5`)
+	checkEval(t, `((fn f (n &key acc) (if (= n 0) acc (f (- n 1) :acc (+ acc n)))) 3 :acc 0)`, `6`)
+	checkEval(t, `(letrec ((f ([a b] &key c) (list a b c))) (f [1 2] :c 3))`, `(1 2 3)`)
+	checkEval(t, `(apply (fn (a &key b) (list a b)) '(1 :b 2))`, `(1 2)`)
+	checkEvalError(t, `(def (connect host &key port) port) (connect "h" :bogus 1)`, "Unknown keyword argument :bogus")
+	checkEvalError(t, `(def (connect host &key port) port) (connect "h" :port)`, "without a value")
+	checkEvalError(t, `(def (connect host &key port) port) (connect "h" 1 2)`, "Expected keyword argument")
+	checkEvalError(t, `(def (f a &key b) a) (f)`, "Wrong number of arguments")
+	checkEvalError(t, `((fn (a) a) 1 :b 2)`, "Wrong number of arguments")
+	checkEvalError(t, `(fn (a &key [b]) a)`, "expected name after &key")
+}
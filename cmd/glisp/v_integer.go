@@ -109,6 +109,22 @@ func (v *vInteger) asArray() ([]Value, bool) {
 	return nil, false
 }
 
-func (v *vInteger) asDict() (map[string]Value, bool) {
+func (v *vInteger) asDict() (*dictMap, bool) {
+	return nil, false
+}
+
+func (v *vInteger) asChar() (rune, bool) {
+	return 0, false
+}
+
+func (v *vInteger) asKeyword() (string, bool) {
+	return "", false
+}
+
+func (v *vInteger) asRegex() (*vRegex, bool) {
+	return nil, false
+}
+
+func (v *vInteger) asPort() (*vPort, bool) {
 	return nil, false
 }
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+)
+
+// An iterable is a sequence whose elements are produced on demand
+// each time it is iterated over, such as a range or a user-defined
+// iterable built from a glisp function.
+
+type vIterable struct {
+	name  string
+	start func() (seqIterator, error)
+}
+
+func NewIterable(name string, start func() (seqIterator, error)) Value {
+	return &vIterable{name, start}
+}
+
+func (v *vIterable) Display() string {
+	return fmt.Sprintf("#<%s>", v.name)
+}
+
+func (v *vIterable) DisplayCDR() string {
+	panic(fmt.Sprintf("unchecked access to %s", v.str()))
+}
+
+func (v *vIterable) apply(args []Value) (Value, error) {
+	return nil, fmt.Errorf("Value %s not applicable", v.str())
+}
+
+func (v *vIterable) str() string {
+	return fmt.Sprintf("VIterable[%s]", v.name)
+}
+
+func (v *vIterable) isAtom() bool {
+	return false
+}
+
+func (v *vIterable) isSymbol() bool {
+	return false
+}
+
+func (v *vIterable) isCons() bool {
+	return false
+}
+
+func (v *vIterable) isEmpty() bool {
+	return false
+}
+
+func (v *vIterable) isNumber() bool {
+	return false
+}
+
+func (v *vIterable) isBool() bool {
+	return false
+}
+
+func (v *vIterable) isString() bool {
+	return false
+}
+
+func (v *vIterable) isFunction() bool {
+	return false
+}
+
+func (v *vIterable) isTrue() bool {
+	return true
+}
+
+func (v *vIterable) isNil() bool {
+	return false
+}
+
+func (v *vIterable) isEqual(vv Value) bool {
+	return v == vv // pointer equality
+}
+
+func (v *vIterable) typ() string {
+	return "iterable"
+}
+
+func (v *vIterable) asInteger() (int, bool) {
+	return 0, false
+}
+
+func (v *vIterable) asBoolean() (bool, bool) {
+	return false, false
+}
+
+func (v *vIterable) asString() (string, bool) {
+	return "", false
+}
+
+func (v *vIterable) asSymbol() (string, bool) {
+	return "", false
+}
+
+func (v *vIterable) asCons() (Value, Value, bool) {
+	return nil, nil, false
+}
+
+func (v *vIterable) asReference() (Value, func(Value), bool) {
+	return nil, nil, false
+}
+
+func (v *vIterable) setReference(Value) bool {
+	return false
+}
+
+func (v *vIterable) asArray() ([]Value, bool) {
+	return nil, false
+}
+
+func (v *vIterable) asDict() (*dictMap, bool) {
+	return nil, false
+}
+
+func (v *vIterable) asChar() (rune, bool) {
+	return 0, false
+}
+
+func (v *vIterable) asKeyword() (string, bool) {
+	return "", false
+}
+
+func (v *vIterable) asRegex() (*vRegex, bool) {
+	return nil, false
+}
+
+func (v *vIterable) asPort() (*vPort, bool) {
+	return nil, false
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Keywords are written :name and evaluate to themselves. Like symbols,
+// they are interned.
+
+type vKeyword struct {
+	name string
+}
+
+var keywordTable = struct {
+	sync.Mutex
+	keywords map[string]*vKeyword
+}{keywords: map[string]*vKeyword{}}
+
+func NewKeyword(name string) Value {
+	keywordTable.Lock()
+	defer keywordTable.Unlock()
+	if k, ok := keywordTable.keywords[name]; ok {
+		return k
+	}
+	k := &vKeyword{name}
+	keywordTable.keywords[name] = k
+	return k
+}
+
+func (v *vKeyword) Display() string {
+	return ":" + v.name
+}
+
+func (v *vKeyword) DisplayCDR() string {
+	panic(fmt.Sprintf("unchecked access to %s", v.str()))
+}
+
+func (v *vKeyword) apply(args []Value) (Value, error) {
+	// (:key coll [default]) looks the keyword up in a dict or hash-map
+	if len(args) < 1 || len(args) > 2 {
+		return nil, fmt.Errorf("keyword lookup requires a dict or hash-map and an optional default")
+	}
+	var result Value
+	found := false
+	if m, ok := args[0].(*vMap); ok {
+		result, found, _ = m.content.get(v)
+	} else if content, ok := args[0].asDict(); ok {
+		result, found, _ = content.get(v)
+	} else {
+		return nil, fmt.Errorf("keyword lookup requires a dict or hash-map")
+	}
+	if found {
+		return result, nil
+	}
+	if len(args) > 1 {
+		return args[1], nil
+	}
+	return nil, fmt.Errorf("key %s not found", v.Display())
+}
+
+func (v *vKeyword) str() string {
+	return fmt.Sprintf("VKeyword[%s]", v.name)
+}
+
+func (v *vKeyword) isAtom() bool {
+	return true
+}
+
+func (v *vKeyword) isSymbol() bool {
+	return false
+}
+
+func (v *vKeyword) isCons() bool {
+	return false
+}
+
+func (v *vKeyword) isEmpty() bool {
+	return false
+}
+
+func (v *vKeyword) isNumber() bool {
+	return false
+}
+
+func (v *vKeyword) isBool() bool {
+	return false
+}
+
+func (v *vKeyword) isString() bool {
+	return false
+}
+
+func (v *vKeyword) isFunction() bool {
+	return false
+}
+
+func (v *vKeyword) isTrue() bool {
+	return true
+}
+
+func (v *vKeyword) isNil() bool {
+	return false
+}
+
+func (v *vKeyword) isEqual(vv Value) bool {
+	return v == vv // interned
+}
+
+func (v *vKeyword) typ() string {
+	return "keyword"
+}
+
+func (v *vKeyword) asInteger() (int, bool) {
+	return 0, false
+}
+
+func (v *vKeyword) asBoolean() (bool, bool) {
+	return false, false
+}
+
+func (v *vKeyword) asString() (string, bool) {
+	return "", false
+}
+
+func (v *vKeyword) asSymbol() (string, bool) {
+	return "", false
+}
+
+func (v *vKeyword) asCons() (Value, Value, bool) {
+	return nil, nil, false
+}
+
+func (v *vKeyword) asReference() (Value, func(Value), bool) {
+	return nil, nil, false
+}
+
+func (v *vKeyword) setReference(Value) bool {
+	return false
+}
+
+func (v *vKeyword) asArray() ([]Value, bool) {
+	return nil, false
+}
+
+func (v *vKeyword) asDict() (*dictMap, bool) {
+	return nil, false
+}
+
+func (v *vKeyword) asChar() (rune, bool) {
+	return 0, false
+}
+
+func (v *vKeyword) asKeyword() (string, bool) {
+	return v.name, true
+}
+
+func (v *vKeyword) asRegex() (*vRegex, bool) {
+	return nil, false
+}
+
+func (v *vKeyword) asPort() (*vPort, bool) {
+	return nil, false
+}
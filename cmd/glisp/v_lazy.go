@@ -0,0 +1,177 @@
+package main
+
+import (
+	"fmt"
+)
+
+// A lazy sequence produces its elements on demand from a generator. Each
+// element is produced once and kept in a cell linked to the next one, so
+// the sequence can be walked any number of times and always gives the
+// same elements. Cells no longer reachable from a sequence value can be
+// collected, so a pipeline over a large or infinite input only holds the
+// elements it has not finished with.
+
+type lazyCell struct {
+	next  seqIterator // nil once the cell is realized
+	err   error
+	empty bool
+	item  Value
+	rest  *lazyCell
+}
+
+type vLazySeq struct {
+	head *lazyCell
+}
+
+func NewLazySeq(next seqIterator) Value {
+	return &vLazySeq{&lazyCell{next: next}}
+}
+
+func (c *lazyCell) realize() error {
+	if c.next == nil {
+		return c.err
+	}
+	item, ok, err := c.next()
+	if err != nil {
+		c.err = err
+	} else if !ok {
+		c.empty = true
+	} else {
+		// the generator moves on to the next cell
+		c.item = item
+		c.rest = &lazyCell{next: c.next}
+	}
+	c.next = nil
+	return err
+}
+
+func (v *vLazySeq) iterator() seqIterator {
+	current := v.head
+	return func() (Value, bool, error) {
+		if err := current.realize(); err != nil {
+			return nil, false, err
+		}
+		if current.empty {
+			return nil, false, nil
+		}
+		item := current.item
+		current = current.rest
+		return item, true, nil
+	}
+}
+
+func (v *vLazySeq) Display() string {
+	return "#<lazy-seq>"
+}
+
+func (v *vLazySeq) DisplayCDR() string {
+	panic(fmt.Sprintf("unchecked access to %s", v.str()))
+}
+
+func (v *vLazySeq) apply(args []Value) (Value, error) {
+	return nil, fmt.Errorf("Value %s not applicable", v.str())
+}
+
+func (v *vLazySeq) str() string {
+	return "VLazySeq"
+}
+
+func (v *vLazySeq) isAtom() bool {
+	return false
+}
+
+func (v *vLazySeq) isSymbol() bool {
+	return false
+}
+
+func (v *vLazySeq) isCons() bool {
+	return false
+}
+
+func (v *vLazySeq) isEmpty() bool {
+	return false
+}
+
+func (v *vLazySeq) isNumber() bool {
+	return false
+}
+
+func (v *vLazySeq) isBool() bool {
+	return false
+}
+
+func (v *vLazySeq) isString() bool {
+	return false
+}
+
+func (v *vLazySeq) isFunction() bool {
+	return false
+}
+
+func (v *vLazySeq) isTrue() bool {
+	return true
+}
+
+func (v *vLazySeq) isNil() bool {
+	return false
+}
+
+func (v *vLazySeq) isEqual(vv Value) bool {
+	return v == vv // pointer equality
+}
+
+func (v *vLazySeq) typ() string {
+	return "lazy-seq"
+}
+
+func (v *vLazySeq) asInteger() (int, bool) {
+	return 0, false
+}
+
+func (v *vLazySeq) asBoolean() (bool, bool) {
+	return false, false
+}
+
+func (v *vLazySeq) asString() (string, bool) {
+	return "", false
+}
+
+func (v *vLazySeq) asSymbol() (string, bool) {
+	return "", false
+}
+
+func (v *vLazySeq) asCons() (Value, Value, bool) {
+	return nil, nil, false
+}
+
+func (v *vLazySeq) asReference() (Value, func(Value), bool) {
+	return nil, nil, false
+}
+
+func (v *vLazySeq) setReference(Value) bool {
+	return false
+}
+
+func (v *vLazySeq) asArray() ([]Value, bool) {
+	return nil, false
+}
+
+func (v *vLazySeq) asDict() (*dictMap, bool) {
+	return nil, false
+}
+
+func (v *vLazySeq) asChar() (rune, bool) {
+	return 0, false
+}
+
+func (v *vLazySeq) asKeyword() (string, bool) {
+	return "", false
+}
+
+func (v *vLazySeq) asRegex() (*vRegex, bool) {
+	return nil, false
+}
+
+func (v *vLazySeq) asPort() (*vPort, bool) {
+	return nil, false
+}
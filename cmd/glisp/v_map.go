@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+type vMap struct {
+	content *pmap
+}
+
+func NewMap(m *pmap) Value {
+	return &vMap{m}
+}
+
+func (v *vMap) Display() string {
+	entries := v.content.entries()
+	s := make([]string, len(entries))
+	for i, e := range entries {
+		s[i] = fmt.Sprintf("%s %s", e.key.Display(), e.value.Display())
+	}
+	return fmt.Sprintf("{%s}", strings.Join(s, " "))
+}
+
+func (v *vMap) DisplayCDR() string {
+	panic(fmt.Sprintf("unchecked access to %s", v.str()))
+}
+
+func (v *vMap) apply(args []Value) (Value, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("hash-map indexing requires a key")
+	}
+	result, ok, err := v.content.get(args[0])
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("key %s not in hash-map", args[0].Display())
+	}
+	return result, nil
+}
+
+func (v *vMap) str() string {
+	entries := v.content.entries()
+	s := make([]string, len(entries))
+	for i, e := range entries {
+		s[i] = fmt.Sprintf("[%s %s]", e.key.str(), e.value.str())
+	}
+	return fmt.Sprintf("VMap[%s]", strings.Join(s, " "))
+}
+
+func (v *vMap) isAtom() bool {
+	return false
+}
+
+func (v *vMap) isSymbol() bool {
+	return false
+}
+
+func (v *vMap) isCons() bool {
+	return false
+}
+
+func (v *vMap) isEmpty() bool {
+	return false
+}
+
+func (v *vMap) isNumber() bool {
+	return false
+}
+
+func (v *vMap) isBool() bool {
+	return false
+}
+
+func (v *vMap) isString() bool {
+	return false
+}
+
+func (v *vMap) isFunction() bool {
+	return false
+}
+
+func (v *vMap) isTrue() bool {
+	return false
+}
+
+func (v *vMap) isNil() bool {
+	return false
+}
+
+func (v *vMap) isEqual(vv Value) bool {
+	return equalValues(v, vv)
+}
+
+func (v *vMap) typ() string {
+	return "hash-map"
+}
+
+func (v *vMap) asInteger() (int, bool) {
+	return 0, false
+}
+
+func (v *vMap) asBoolean() (bool, bool) {
+	return false, false
+}
+
+func (v *vMap) asString() (string, bool) {
+	return "", false
+}
+
+func (v *vMap) asSymbol() (string, bool) {
+	return "", false
+}
+
+func (v *vMap) asCons() (Value, Value, bool) {
+	return nil, nil, false
+}
+
+func (v *vMap) asReference() (Value, func(Value), bool) {
+	return nil, nil, false
+}
+
+func (v *vMap) setReference(Value) bool {
+	return false
+}
+
+func (v *vMap) asArray() ([]Value, bool) {
+	return nil, false
+}
+
+func (v *vMap) asDict() (*dictMap, bool) {
+	return nil, false
+}
+
+func (v *vMap) asChar() (rune, bool) {
+	return 0, false
+}
+
+func (v *vMap) asKeyword() (string, bool) {
+	return "", false
+}
+
+func (v *vMap) asRegex() (*vRegex, bool) {
+	return nil, false
+}
+
+func (v *vMap) asPort() (*vPort, bool) {
+	return nil, false
+}
@@ -108,6 +108,22 @@ func (v *vNil) asArray() ([]Value, bool) {
 	return nil, false
 }
 
-func (v *vNil) asDict() (map[string]Value, bool) {
+func (v *vNil) asDict() (*dictMap, bool) {
+	return nil, false
+}
+
+func (v *vNil) asChar() (rune, bool) {
+	return 0, false
+}
+
+func (v *vNil) asKeyword() (string, bool) {
+	return "", false
+}
+
+func (v *vNil) asRegex() (*vRegex, bool) {
+	return nil, false
+}
+
+func (v *vNil) asPort() (*vPort, bool) {
 	return nil, false
 }
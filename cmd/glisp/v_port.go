@@ -0,0 +1,284 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// A port wraps a reader and/or a writer (files, standard streams,
+// string buffers) for incremental input and output.
+
+type vPort struct {
+	name    string
+	reader  *bufio.Reader
+	source  io.Reader
+	writer  io.Writer
+	closer  io.Closer
+	pending string // input read ahead by the s-expression reader
+	closed  bool
+}
+
+func NewInputPort(name string, r io.Reader, c io.Closer) *vPort {
+	return &vPort{name: name, reader: bufio.NewReader(r), source: r, closer: c}
+}
+
+func NewOutputPort(name string, w io.Writer, c io.Closer) *vPort {
+	return &vPort{name: name, writer: w, closer: c}
+}
+
+func (v *vPort) Display() string {
+	return fmt.Sprintf("#<port %s>", v.name)
+}
+
+func (v *vPort) DisplayCDR() string {
+	panic(fmt.Sprintf("unchecked access to %s", v.str()))
+}
+
+func (v *vPort) apply(args []Value) (Value, error) {
+	return nil, fmt.Errorf("Value %s not applicable", v.str())
+}
+
+func (v *vPort) str() string {
+	return fmt.Sprintf("VPort[%s]", v.name)
+}
+
+func (v *vPort) isAtom() bool {
+	return false
+}
+
+func (v *vPort) isSymbol() bool {
+	return false
+}
+
+func (v *vPort) isCons() bool {
+	return false
+}
+
+func (v *vPort) isEmpty() bool {
+	return false
+}
+
+func (v *vPort) isNumber() bool {
+	return false
+}
+
+func (v *vPort) isBool() bool {
+	return false
+}
+
+func (v *vPort) isString() bool {
+	return false
+}
+
+func (v *vPort) isFunction() bool {
+	return false
+}
+
+func (v *vPort) isTrue() bool {
+	return true
+}
+
+func (v *vPort) isNil() bool {
+	return false
+}
+
+func (v *vPort) isEqual(vv Value) bool {
+	return v == vv // pointer equality
+}
+
+func (v *vPort) typ() string {
+	return "port"
+}
+
+func (v *vPort) asInteger() (int, bool) {
+	return 0, false
+}
+
+func (v *vPort) asBoolean() (bool, bool) {
+	return false, false
+}
+
+func (v *vPort) asString() (string, bool) {
+	return "", false
+}
+
+func (v *vPort) asSymbol() (string, bool) {
+	return "", false
+}
+
+func (v *vPort) asCons() (Value, Value, bool) {
+	return nil, nil, false
+}
+
+func (v *vPort) asReference() (Value, func(Value), bool) {
+	return nil, nil, false
+}
+
+func (v *vPort) setReference(Value) bool {
+	return false
+}
+
+func (v *vPort) asArray() ([]Value, bool) {
+	return nil, false
+}
+
+func (v *vPort) asDict() (*dictMap, bool) {
+	return nil, false
+}
+
+func (v *vPort) asChar() (rune, bool) {
+	return 0, false
+}
+
+func (v *vPort) asKeyword() (string, bool) {
+	return "", false
+}
+
+func (v *vPort) asRegex() (*vRegex, bool) {
+	return nil, false
+}
+
+func (v *vPort) asPort() (*vPort, bool) {
+	return v, true
+}
+
+func (v *vPort) checkInput() error {
+	if v.closed {
+		return fmt.Errorf("port %s is closed", v.name)
+	}
+	if v.reader == nil {
+		return fmt.Errorf("port %s is not an input port", v.name)
+	}
+	return nil
+}
+
+func (v *vPort) checkOutput() error {
+	if v.closed {
+		return fmt.Errorf("port %s is closed", v.name)
+	}
+	if v.writer == nil {
+		return fmt.Errorf("port %s is not an output port", v.name)
+	}
+	return nil
+}
+
+func (v *vPort) input() (io.Reader, error) {
+	// the rest of the input, for a command to read: the file itself when
+	// nothing has been read ahead, so that a command can use a terminal
+	if err := v.checkInput(); err != nil {
+		return nil, err
+	}
+	if f, ok := v.source.(*os.File); ok && v.pending == "" && v.reader.Buffered() == 0 {
+		return f, nil
+	}
+	pending := v.pending
+	v.pending = ""
+	return io.MultiReader(strings.NewReader(pending), v.reader), nil
+}
+
+func (v *vPort) readLine() (string, error) {
+	// returns the next line including its newline, or io.EOF
+	// when no input is left, like bufio.Reader.ReadString
+	if err := v.checkInput(); err != nil {
+		return "", err
+	}
+	if i := strings.Index(v.pending, "\n"); i >= 0 {
+		line := v.pending[:i+1]
+		v.pending = v.pending[i+1:]
+		return line, nil
+	}
+	line, err := v.reader.ReadString('\n')
+	line = v.pending + line
+	v.pending = ""
+	if err == io.EOF && line != "" {
+		return line, nil
+	}
+	return line, err
+}
+
+func (v *vPort) readRune() (rune, error) {
+	if err := v.checkInput(); err != nil {
+		return 0, err
+	}
+	if v.pending != "" {
+		r := []rune(v.pending)[0]
+		v.pending = v.pending[len(string(r)):]
+		return r, nil
+	}
+	r, _, err := v.reader.ReadRune()
+	return r, err
+}
+
+func (v *vPort) readAll() (string, error) {
+	if err := v.checkInput(); err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	b.WriteString(v.pending)
+	v.pending = ""
+	if _, err := io.Copy(&b, v.reader); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+func (v *vPort) readValue() (Value, error) {
+	// read one s-expression, pulling in more lines while the
+	// text read so far is incomplete; returns nil at end of input
+	text := v.pending
+	v.pending = ""
+	for {
+		var readErr error
+		if strings.TrimSpace(text) != "" {
+			result, rest, err := read(text)
+			if err == nil {
+				v.pending = rest
+				return literalValue(result)
+			}
+			if end, complete := formExtent(text); complete {
+				// malformed rather than incomplete: skip the bad form
+				// and leave the rest of the input for the next read
+				v.pending = text[end:]
+				return nil, err
+			}
+			readErr = err
+		}
+		line, err := v.readLine()
+		if err == io.EOF {
+			return nil, readErr
+		}
+		if err != nil {
+			return nil, err
+		}
+		text += line
+	}
+}
+
+func (v *vPort) write(str string) error {
+	if err := v.checkOutput(); err != nil {
+		return err
+	}
+	_, err := io.WriteString(v.writer, str)
+	return err
+}
+
+func (v *vPort) close() error {
+	if v.closed {
+		return nil
+	}
+	v.closed = true
+	if v.closer != nil {
+		return v.closer.Close()
+	}
+	return nil
+}
+
+func (v *vPort) outputString() (string, error) {
+	if b, ok := v.writer.(*strings.Builder); ok {
+		return b.String(), nil
+	}
+	return "", fmt.Errorf("port %s is not an output string port", v.name)
+}
@@ -0,0 +1,51 @@
+package main
+
+import "strings"
+import "testing"
+
+func TestReadValueSkipsMalformedForm(t *testing.T) {
+	p := NewInputPort("test", strings.NewReader("3 ) (4)\n(5\n 6)\n"), nil)
+	expected := []string{"3", "error", "(4)", "(5 6)", "eof"}
+	for _, exp := range expected {
+		v, err := p.readValue()
+		got := "eof"
+		if err != nil {
+			got = "error"
+		} else if v != nil {
+			got = v.Display()
+		}
+		if got != exp {
+			t.Errorf("expected %s but got %s", exp, got)
+		}
+	}
+}
+
+func TestFormExtent(t *testing.T) {
+	cases := []struct {
+		text     string
+		end      int
+		complete bool
+	}{
+		{"abc def", 3, true},
+		{"  (a (b) c) d", 11, true},
+		{"(a [b\n", 0, false},
+		{") (4)", 1, true},
+		{"(a ]", 4, true},
+		{"\"a b\" c", 5, true},
+		{"#\\( x", 3, true},
+		{`#r"a\"b" c`, 8, true},
+		{"#{1 2} x", 6, true},
+		{"; comment\n", 0, false},
+		{"'", 0, false},
+	}
+	for _, c := range cases {
+		end, complete := formExtent(c.text)
+		if end != c.end || complete != c.complete {
+			t.Errorf("%q - expected %d %v but got %d %v", c.text, c.end, c.complete, end, complete)
+		}
+	}
+}
+
+func TestCloseStdin(t *testing.T) {
+	checkEvalError(t, `(close stdin)`, "cannot close stdin")
+}
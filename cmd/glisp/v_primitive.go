@@ -109,6 +109,22 @@ func (v *vPrimitive) asArray() ([]Value, bool) {
 	return nil, false
 }
 
-func (v *vPrimitive) asDict() (map[string]Value, bool) {
+func (v *vPrimitive) asDict() (*dictMap, bool) {
+	return nil, false
+}
+
+func (v *vPrimitive) asChar() (rune, bool) {
+	return 0, false
+}
+
+func (v *vPrimitive) asKeyword() (string, bool) {
+	return "", false
+}
+
+func (v *vPrimitive) asRegex() (*vRegex, bool) {
+	return nil, false
+}
+
+func (v *vPrimitive) asPort() (*vPort, bool) {
 	return nil, false
 }
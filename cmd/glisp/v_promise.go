@@ -0,0 +1,157 @@
+package main
+
+import (
+	"fmt"
+)
+
+// A promise holds a computation delayed with (delay expr), which runs
+// the first time the promise is forced; its value is then remembered.
+
+type vPromise struct {
+	thunk  Value
+	value  Value
+	forced bool
+}
+
+func NewPromise(thunk Value) Value {
+	return &vPromise{thunk: thunk}
+}
+
+func (v *vPromise) force() (Value, error) {
+	if !v.forced {
+		result, err := applyFirst(v.thunk, []Value{})
+		if err != nil {
+			return nil, err
+		}
+		if v.forced {
+			// forced again while running
+			return v.value, nil
+		}
+		v.value = result
+		v.forced = true
+		v.thunk = nil
+	}
+	return v.value, nil
+}
+
+func (v *vPromise) Display() string {
+	if v.forced {
+		return fmt.Sprintf("#<promise %s>", v.value.Display())
+	}
+	return "#<promise>"
+}
+
+func (v *vPromise) DisplayCDR() string {
+	panic(fmt.Sprintf("unchecked access to %s", v.str()))
+}
+
+func (v *vPromise) apply(args []Value) (Value, error) {
+	return nil, fmt.Errorf("Value %s not applicable", v.str())
+}
+
+func (v *vPromise) str() string {
+	if v.forced {
+		return fmt.Sprintf("VPromise[%s]", v.value.str())
+	}
+	return "VPromise[]"
+}
+
+func (v *vPromise) isAtom() bool {
+	return false
+}
+
+func (v *vPromise) isSymbol() bool {
+	return false
+}
+
+func (v *vPromise) isCons() bool {
+	return false
+}
+
+func (v *vPromise) isEmpty() bool {
+	return false
+}
+
+func (v *vPromise) isNumber() bool {
+	return false
+}
+
+func (v *vPromise) isBool() bool {
+	return false
+}
+
+func (v *vPromise) isString() bool {
+	return false
+}
+
+func (v *vPromise) isFunction() bool {
+	return false
+}
+
+func (v *vPromise) isTrue() bool {
+	return true
+}
+
+func (v *vPromise) isNil() bool {
+	return false
+}
+
+func (v *vPromise) isEqual(vv Value) bool {
+	return v == vv // pointer equality
+}
+
+func (v *vPromise) typ() string {
+	return "promise"
+}
+
+func (v *vPromise) asInteger() (int, bool) {
+	return 0, false
+}
+
+func (v *vPromise) asBoolean() (bool, bool) {
+	return false, false
+}
+
+func (v *vPromise) asString() (string, bool) {
+	return "", false
+}
+
+func (v *vPromise) asSymbol() (string, bool) {
+	return "", false
+}
+
+func (v *vPromise) asCons() (Value, Value, bool) {
+	return nil, nil, false
+}
+
+func (v *vPromise) asReference() (Value, func(Value), bool) {
+	return nil, nil, false
+}
+
+func (v *vPromise) setReference(Value) bool {
+	return false
+}
+
+func (v *vPromise) asArray() ([]Value, bool) {
+	return nil, false
+}
+
+func (v *vPromise) asDict() (*dictMap, bool) {
+	return nil, false
+}
+
+func (v *vPromise) asChar() (rune, bool) {
+	return 0, false
+}
+
+func (v *vPromise) asKeyword() (string, bool) {
+	return "", false
+}
+
+func (v *vPromise) asRegex() (*vRegex, bool) {
+	return nil, false
+}
+
+func (v *vPromise) asPort() (*vPort, bool) {
+	return nil, false
+}
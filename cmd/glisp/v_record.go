@@ -0,0 +1,144 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+type recordType struct {
+	name   string
+	fields []string
+}
+
+type vRecord struct {
+	rtype  *recordType
+	fields []Value
+}
+
+func NewRecord(rtype *recordType, fields []Value) Value {
+	return &vRecord{rtype, fields}
+}
+
+func (v *vRecord) Display() string {
+	s := []string{v.rtype.name}
+	for i, vv := range v.fields {
+		s = append(s, fmt.Sprintf("%s=%s", v.rtype.fields[i], vv.Display()))
+	}
+	return fmt.Sprintf("#<%s>", strings.Join(s, " "))
+}
+
+func (v *vRecord) DisplayCDR() string {
+	panic(fmt.Sprintf("unchecked access to %s", v.str()))
+}
+
+func (v *vRecord) apply(args []Value) (Value, error) {
+	return nil, fmt.Errorf("Value %s not applicable", v.str())
+}
+
+func (v *vRecord) str() string {
+	s := make([]string, len(v.fields))
+	for i, vv := range v.fields {
+		s[i] = fmt.Sprintf("[%s %s]", v.rtype.fields[i], vv.str())
+	}
+	return fmt.Sprintf("VRecord[%s %s]", v.rtype.name, strings.Join(s, " "))
+}
+
+func (v *vRecord) isAtom() bool {
+	return false
+}
+
+func (v *vRecord) isSymbol() bool {
+	return false
+}
+
+func (v *vRecord) isCons() bool {
+	return false
+}
+
+func (v *vRecord) isEmpty() bool {
+	return false
+}
+
+func (v *vRecord) isNumber() bool {
+	return false
+}
+
+func (v *vRecord) isBool() bool {
+	return false
+}
+
+func (v *vRecord) isString() bool {
+	return false
+}
+
+func (v *vRecord) isFunction() bool {
+	return false
+}
+
+func (v *vRecord) isTrue() bool {
+	return false
+}
+
+func (v *vRecord) isNil() bool {
+	return false
+}
+
+func (v *vRecord) isEqual(vv Value) bool {
+	return equalValues(v, vv)
+}
+
+func (v *vRecord) typ() string {
+	return v.rtype.name
+}
+
+func (v *vRecord) asInteger() (int, bool) {
+	return 0, false
+}
+
+func (v *vRecord) asBoolean() (bool, bool) {
+	return false, false
+}
+
+func (v *vRecord) asString() (string, bool) {
+	return "", false
+}
+
+func (v *vRecord) asSymbol() (string, bool) {
+	return "", false
+}
+
+func (v *vRecord) asCons() (Value, Value, bool) {
+	return nil, nil, false
+}
+
+func (v *vRecord) asReference() (Value, func(Value), bool) {
+	return nil, nil, false
+}
+
+func (v *vRecord) setReference(Value) bool {
+	return false
+}
+
+func (v *vRecord) asArray() ([]Value, bool) {
+	return nil, false
+}
+
+func (v *vRecord) asDict() (*dictMap, bool) {
+	return nil, false
+}
+
+func (v *vRecord) asChar() (rune, bool) {
+	return 0, false
+}
+
+func (v *vRecord) asKeyword() (string, bool) {
+	return "", false
+}
+
+func (v *vRecord) asRegex() (*vRegex, bool) {
+	return nil, false
+}
+
+func (v *vRecord) asPort() (*vPort, bool) {
+	return nil, false
+}
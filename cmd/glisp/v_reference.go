@@ -76,7 +76,7 @@ func (v *vReference) isNil() bool {
 }
 
 func (v *vReference) isEqual(vv Value) bool {
-	return v == vv // pointer equality
+	return equalValues(v, vv)
 }
 
 func (v *vReference) typ() string {
@@ -119,6 +119,22 @@ func (v *vReference) asArray() ([]Value, bool) {
 	return nil, false
 }
 
-func (v *vReference) asDict() (map[string]Value, bool) {
+func (v *vReference) asDict() (*dictMap, bool) {
+	return nil, false
+}
+
+func (v *vReference) asChar() (rune, bool) {
+	return 0, false
+}
+
+func (v *vReference) asKeyword() (string, bool) {
+	return "", false
+}
+
+func (v *vReference) asRegex() (*vRegex, bool) {
+	return nil, false
+}
+
+func (v *vReference) asPort() (*vPort, bool) {
 	return nil, false
 }
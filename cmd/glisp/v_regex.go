@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+type vRegex struct {
+	re *regexp.Regexp
+}
+
+func NewRegex(re *regexp.Regexp) Value {
+	return &vRegex{re}
+}
+
+func (v *vRegex) Display() string {
+	return fmt.Sprintf("#r\"%s\"", strings.ReplaceAll(v.re.String(), `"`, `\"`))
+}
+
+func (v *vRegex) DisplayCDR() string {
+	panic(fmt.Sprintf("unchecked access to %s", v.str()))
+}
+
+func (v *vRegex) apply(args []Value) (Value, error) {
+	return nil, fmt.Errorf("Value %s not applicable", v.str())
+}
+
+func (v *vRegex) str() string {
+	return fmt.Sprintf("VRegex[%s]", v.re.String())
+}
+
+func (v *vRegex) isAtom() bool {
+	return true
+}
+
+func (v *vRegex) isSymbol() bool {
+	return false
+}
+
+func (v *vRegex) isCons() bool {
+	return false
+}
+
+func (v *vRegex) isEmpty() bool {
+	return false
+}
+
+func (v *vRegex) isNumber() bool {
+	return false
+}
+
+func (v *vRegex) isBool() bool {
+	return false
+}
+
+func (v *vRegex) isString() bool {
+	return false
+}
+
+func (v *vRegex) isFunction() bool {
+	return false
+}
+
+func (v *vRegex) isTrue() bool {
+	return true
+}
+
+func (v *vRegex) isNil() bool {
+	return false
+}
+
+func (v *vRegex) isEqual(vv Value) bool {
+	other, ok := vv.asRegex()
+	return ok && v.re.String() == other.re.String()
+}
+
+func (v *vRegex) typ() string {
+	return "regex"
+}
+
+func (v *vRegex) asInteger() (int, bool) {
+	return 0, false
+}
+
+func (v *vRegex) asBoolean() (bool, bool) {
+	return false, false
+}
+
+func (v *vRegex) asString() (string, bool) {
+	return "", false
+}
+
+func (v *vRegex) asSymbol() (string, bool) {
+	return "", false
+}
+
+func (v *vRegex) asCons() (Value, Value, bool) {
+	return nil, nil, false
+}
+
+func (v *vRegex) asReference() (Value, func(Value), bool) {
+	return nil, nil, false
+}
+
+func (v *vRegex) setReference(Value) bool {
+	return false
+}
+
+func (v *vRegex) asArray() ([]Value, bool) {
+	return nil, false
+}
+
+func (v *vRegex) asDict() (*dictMap, bool) {
+	return nil, false
+}
+
+func (v *vRegex) asChar() (rune, bool) {
+	return 0, false
+}
+
+func (v *vRegex) asKeyword() (string, bool) {
+	return "", false
+}
+
+func (v *vRegex) asRegex() (*vRegex, bool) {
+	return v, true
+}
+
+func (v *vRegex) asPort() (*vPort, bool) {
+	return nil, false
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+type vSet struct {
+	content *pmap // elements map to themselves
+}
+
+func NewSet(m *pmap) Value {
+	return &vSet{m}
+}
+
+func (v *vSet) Display() string {
+	entries := v.content.entries()
+	s := make([]string, len(entries))
+	for i, e := range entries {
+		s[i] = e.key.Display()
+	}
+	return fmt.Sprintf("#{%s}", strings.Join(s, " "))
+}
+
+func (v *vSet) DisplayCDR() string {
+	panic(fmt.Sprintf("unchecked access to %s", v.str()))
+}
+
+func (v *vSet) apply(args []Value) (Value, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("set membership requires an element")
+	}
+	_, ok, err := v.content.get(args[0])
+	if err != nil {
+		return nil, err
+	}
+	return NewBoolean(ok), nil
+}
+
+func (v *vSet) str() string {
+	entries := v.content.entries()
+	s := make([]string, len(entries))
+	for i, e := range entries {
+		s[i] = e.key.str()
+	}
+	return fmt.Sprintf("VSet[%s]", strings.Join(s, " "))
+}
+
+func (v *vSet) isAtom() bool {
+	return false
+}
+
+func (v *vSet) isSymbol() bool {
+	return false
+}
+
+func (v *vSet) isCons() bool {
+	return false
+}
+
+func (v *vSet) isEmpty() bool {
+	return false
+}
+
+func (v *vSet) isNumber() bool {
+	return false
+}
+
+func (v *vSet) isBool() bool {
+	return false
+}
+
+func (v *vSet) isString() bool {
+	return false
+}
+
+func (v *vSet) isFunction() bool {
+	return false
+}
+
+func (v *vSet) isTrue() bool {
+	return false
+}
+
+func (v *vSet) isNil() bool {
+	return false
+}
+
+func (v *vSet) isEqual(vv Value) bool {
+	return equalValues(v, vv)
+}
+
+func (v *vSet) typ() string {
+	return "set"
+}
+
+func (v *vSet) asInteger() (int, bool) {
+	return 0, false
+}
+
+func (v *vSet) asBoolean() (bool, bool) {
+	return false, false
+}
+
+func (v *vSet) asString() (string, bool) {
+	return "", false
+}
+
+func (v *vSet) asSymbol() (string, bool) {
+	return "", false
+}
+
+func (v *vSet) asCons() (Value, Value, bool) {
+	return nil, nil, false
+}
+
+func (v *vSet) asReference() (Value, func(Value), bool) {
+	return nil, nil, false
+}
+
+func (v *vSet) setReference(Value) bool {
+	return false
+}
+
+func (v *vSet) asArray() ([]Value, bool) {
+	return nil, false
+}
+
+func (v *vSet) asDict() (*dictMap, bool) {
+	return nil, false
+}
+
+func (v *vSet) asChar() (rune, bool) {
+	return 0, false
+}
+
+func (v *vSet) asKeyword() (string, bool) {
+	return "", false
+}
+
+func (v *vSet) asRegex() (*vRegex, bool) {
+	return nil, false
+}
+
+func (v *vSet) asPort() (*vPort, bool) {
+	return nil, false
+}
@@ -109,6 +109,22 @@ func (v *vString) asArray() ([]Value, bool) {
 	return nil, false
 }
 
-func (v *vString) asDict() (map[string]Value, bool) {
+func (v *vString) asDict() (*dictMap, bool) {
+	return nil, false
+}
+
+func (v *vString) asChar() (rune, bool) {
+	return 0, false
+}
+
+func (v *vString) asKeyword() (string, bool) {
+	return "", false
+}
+
+func (v *vString) asRegex() (*vRegex, bool) {
+	return nil, false
+}
+
+func (v *vString) asPort() (*vPort, bool) {
 	return nil, false
 }
@@ -2,14 +2,60 @@ package main
 
 import (
 	"fmt"
+	"sync"
 )
 
 type vSymbol struct {
 	name string
+	id   int // 0 for interned symbols
 }
 
+// Symbols are interned: there is a single symbol for each name, so two
+// symbols are equal exactly when they are the same pointer, and every use
+// of a name shares the string stored in its symbol. Symbols made by gensym
+// are not interned, so they differ from every other symbol, including one
+// read or built from the same name.
+
+var symbolTable = struct {
+	sync.Mutex
+	symbols map[string]*vSymbol
+	counter int
+}{symbols: map[string]*vSymbol{}}
+
 func NewSymbol(name string) Value {
-	return &vSymbol{name}
+	return intern(name)
+}
+
+func intern(name string) *vSymbol {
+	symbolTable.Lock()
+	defer symbolTable.Unlock()
+	if s, ok := symbolTable.symbols[name]; ok {
+		return s
+	}
+	s := &vSymbol{name: name}
+	symbolTable.symbols[name] = s
+	return s
+}
+
+func gensym(prefix string) *vSymbol {
+	// an uninterned symbol, named so as not to look like an existing one
+	symbolTable.Lock()
+	defer symbolTable.Unlock()
+	for {
+		symbolTable.counter += 1
+		name := fmt.Sprintf("%s%d", prefix, symbolTable.counter)
+		if _, ok := symbolTable.symbols[name]; !ok {
+			return &vSymbol{name: name, id: symbolTable.counter}
+		}
+	}
+}
+
+func symbolNames(syms []*vSymbol) []string {
+	names := make([]string, len(syms))
+	for i, sym := range syms {
+		names[i] = sym.name
+	}
+	return names
 }
 
 func (v *vSymbol) Display() string {
@@ -69,8 +115,7 @@ func (v *vSymbol) isNil() bool {
 }
 
 func (v *vSymbol) isEqual(vv Value) bool {
-	name, ok := vv.asSymbol()
-	return ok && v.name == name
+	return v == vv // interned
 }
 
 func (v *vSymbol) typ() string {
@@ -109,6 +154,22 @@ func (v *vSymbol) asArray() ([]Value, bool) {
 	return nil, false
 }
 
-func (v *vSymbol) asDict() (map[string]Value, bool) {
+func (v *vSymbol) asDict() (*dictMap, bool) {
+	return nil, false
+}
+
+func (v *vSymbol) asChar() (rune, bool) {
+	return 0, false
+}
+
+func (v *vSymbol) asKeyword() (string, bool) {
+	return "", false
+}
+
+func (v *vSymbol) asRegex() (*vRegex, bool) {
+	return nil, false
+}
+
+func (v *vSymbol) asPort() (*vPort, bool) {
 	return nil, false
 }
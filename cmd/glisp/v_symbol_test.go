@@ -0,0 +1,20 @@
+package main
+
+import "testing"
+
+func TestGensymIsUninterned(t *testing.T) {
+	checkEval(t, `(def g (gensym)) (eq? g (string->symbol (symbol->string g)))`, `#f`)
+	checkEval(t, `(def g (gensym)) (eq? g g)`, `#t`)
+	checkEval(t, `(eq? (gensym) (gensym))`, `#f`)
+	checkEval(t, `(def g (gensym)) (length (dict-keys (dict (list g 1) (list (string->symbol (symbol->string g)) 2))))`, `2`)
+	checkEval(t, `(eq? 'abc (string->symbol "abc"))`, `#t`)
+}
+
+func TestKeywords(t *testing.T) {
+	checkEval(t, `:a`, `:a`)
+	checkEval(t, `(list (keyword? :a) (keyword? 'a) (symbol? :a))`, `(#t #f #f)`)
+	checkEval(t, `(keyword->string :a)`, `"a"`)
+	checkEval(t, `(eq? (string->keyword "a") :a)`, `#t`)
+	checkEval(t, `(get {:a 1 :b 2} :b)`, `2`)
+	checkEval(t, `(length (dict-keys (dict '(:a 1) '(a 2))))`, `2`)
+}
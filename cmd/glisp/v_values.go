@@ -0,0 +1,171 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Multiple values, as returned by (values v ...). They are taken apart
+// by call-with-values, let-values and receive; anywhere else only the
+// first value is used, or nil when there are none.
+
+type vValues struct {
+	content []Value
+}
+
+func NewValues(vs []Value) Value {
+	if len(vs) == 1 {
+		return vs[0]
+	}
+	return &vValues{vs}
+}
+
+func firstValue(v Value) Value {
+	if vs, ok := v.(*vValues); ok {
+		if len(vs.content) == 0 {
+			return &vNil{}
+		}
+		return vs.content[0]
+	}
+	return v
+}
+
+func applyFirst(f Value, args []Value) (Value, error) {
+	// for primitives that use the result of calling a function
+	v, err := f.apply(args)
+	if err != nil {
+		return nil, err
+	}
+	return firstValue(v), nil
+}
+
+func valuesToSlice(v Value) []Value {
+	if vs, ok := v.(*vValues); ok {
+		return vs.content
+	}
+	return []Value{v}
+}
+
+func (v *vValues) Display() string {
+	strs := make([]string, len(v.content))
+	for i, item := range v.content {
+		strs[i] = item.Display()
+	}
+	return strings.Join(strs, " ")
+}
+
+func (v *vValues) DisplayCDR() string {
+	panic(fmt.Sprintf("unchecked access to %s", v.str()))
+}
+
+func (v *vValues) apply(args []Value) (Value, error) {
+	return nil, fmt.Errorf("Value %s not applicable", v.str())
+}
+
+func (v *vValues) str() string {
+	strs := make([]string, len(v.content))
+	for i, item := range v.content {
+		strs[i] = item.str()
+	}
+	return fmt.Sprintf("VValues[%s]", strings.Join(strs, " "))
+}
+
+func (v *vValues) isAtom() bool {
+	return false
+}
+
+func (v *vValues) isSymbol() bool {
+	return false
+}
+
+func (v *vValues) isCons() bool {
+	return false
+}
+
+func (v *vValues) isEmpty() bool {
+	return false
+}
+
+func (v *vValues) isNumber() bool {
+	return false
+}
+
+func (v *vValues) isBool() bool {
+	return false
+}
+
+func (v *vValues) isString() bool {
+	return false
+}
+
+func (v *vValues) isFunction() bool {
+	return false
+}
+
+func (v *vValues) isTrue() bool {
+	return true
+}
+
+func (v *vValues) isNil() bool {
+	return false
+}
+
+func (v *vValues) isEqual(vv Value) bool {
+	return v == vv // pointer equality
+}
+
+func (v *vValues) typ() string {
+	return "values"
+}
+
+func (v *vValues) asInteger() (int, bool) {
+	return 0, false
+}
+
+func (v *vValues) asBoolean() (bool, bool) {
+	return false, false
+}
+
+func (v *vValues) asString() (string, bool) {
+	return "", false
+}
+
+func (v *vValues) asSymbol() (string, bool) {
+	return "", false
+}
+
+func (v *vValues) asCons() (Value, Value, bool) {
+	return nil, nil, false
+}
+
+func (v *vValues) asReference() (Value, func(Value), bool) {
+	return nil, nil, false
+}
+
+func (v *vValues) setReference(Value) bool {
+	return false
+}
+
+func (v *vValues) asArray() ([]Value, bool) {
+	return nil, false
+}
+
+func (v *vValues) asDict() (*dictMap, bool) {
+	return nil, false
+}
+
+func (v *vValues) asChar() (rune, bool) {
+	return 0, false
+}
+
+func (v *vValues) asKeyword() (string, bool) {
+	return "", false
+}
+
+func (v *vValues) asRegex() (*vRegex, bool) {
+	return nil, false
+}
+
+func (v *vValues) asPort() (*vPort, bool) {
+	return nil, false
+}
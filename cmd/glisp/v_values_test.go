@@ -0,0 +1,17 @@
+package main
+
+import "testing"
+
+func TestValuesInPrimitives(t *testing.T) {
+	checkEval(t, `(map (fn (x) (values x 1)) (list 1 2))`, `(1 2)`)
+	checkEval(t, `(filter (fn (x) (values (> x 1) #t)) (list 1 2 3))`, `(2 3)`)
+	checkEval(t, `(foldl (fn (acc x) (values (+ acc x) 0)) (list 1 2 3) 0)`, `6`)
+	checkEval(t, `(->list (map (fn (x) (values x 0)) (lazy (list 1 2))))`, `(1 2)`)
+	checkEval(t, `(force (delay (values 1 2)))`, `1`)
+	checkEval(t, `(+ 1 (values 10 20))`, `11`)
+	checkEval(t, `(call-with-values (fn () (values 1 2)) +)`, `3`)
+	checkEval(t, `(receive (a b) (apply values '(1 2)) (list a b))`, `(1 2)`)
+	checkEval(t, `(let-values (((a b) (values 1 2)) ((c) (values 3))) (list a b c))`, `(1 2 3)`)
+	checkEval(t, `(let ((a 1)) (let-values (((a) (values 2)) ((b) (values a))) b))`, `1`)
+	checkEvalError(t, `(receive (a b) (values 1) a)`, "expected 2 values but got 1")
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+type vVector struct {
+	content *pvector
+}
+
+func NewVector(vs []Value) Value {
+	return &vVector{pvectorFromSlice(vs)}
+}
+
+func (v *vVector) Display() string {
+	items := v.content.toSlice()
+	s := make([]string, len(items))
+	for i, vv := range items {
+		s[i] = vv.Display()
+	}
+	return fmt.Sprintf("[%s]", strings.Join(s, " "))
+}
+
+func (v *vVector) DisplayCDR() string {
+	panic(fmt.Sprintf("unchecked access to %s", v.str()))
+}
+
+func (v *vVector) apply(args []Value) (Value, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("vector indexing requires an index")
+	}
+	idx, ok := args[0].asInteger()
+	if !ok {
+		return nil, fmt.Errorf("vector indexing requires an integer index")
+	}
+	if idx < 0 || idx >= v.content.count {
+		return nil, fmt.Errorf("vector index out of bounds %d", idx)
+	}
+	return v.content.nth(idx), nil
+}
+
+func (v *vVector) str() string {
+	items := v.content.toSlice()
+	s := make([]string, len(items))
+	for i, vv := range items {
+		s[i] = vv.str()
+	}
+	return fmt.Sprintf("VVector[%s]", strings.Join(s, " "))
+}
+
+func (v *vVector) isAtom() bool {
+	return false
+}
+
+func (v *vVector) isSymbol() bool {
+	return false
+}
+
+func (v *vVector) isCons() bool {
+	return false
+}
+
+func (v *vVector) isEmpty() bool {
+	return false
+}
+
+func (v *vVector) isNumber() bool {
+	return false
+}
+
+func (v *vVector) isBool() bool {
+	return false
+}
+
+func (v *vVector) isString() bool {
+	return false
+}
+
+func (v *vVector) isFunction() bool {
+	return false
+}
+
+func (v *vVector) isTrue() bool {
+	return false
+}
+
+func (v *vVector) isNil() bool {
+	return false
+}
+
+func (v *vVector) isEqual(vv Value) bool {
+	return equalValues(v, vv)
+}
+
+func (v *vVector) typ() string {
+	return "vector"
+}
+
+func (v *vVector) asInteger() (int, bool) {
+	return 0, false
+}
+
+func (v *vVector) asBoolean() (bool, bool) {
+	return false, false
+}
+
+func (v *vVector) asString() (string, bool) {
+	return "", false
+}
+
+func (v *vVector) asSymbol() (string, bool) {
+	return "", false
+}
+
+func (v *vVector) asCons() (Value, Value, bool) {
+	return nil, nil, false
+}
+
+func (v *vVector) asReference() (Value, func(Value), bool) {
+	return nil, nil, false
+}
+
+func (v *vVector) setReference(Value) bool {
+	return false
+}
+
+func (v *vVector) asArray() ([]Value, bool) {
+	return nil, false
+}
+
+func (v *vVector) asDict() (*dictMap, bool) {
+	return nil, false
+}
+
+func (v *vVector) asChar() (rune, bool) {
+	return 0, false
+}
+
+func (v *vVector) asKeyword() (string, bool) {
+	return "", false
+}
+
+func (v *vVector) asRegex() (*vRegex, bool) {
+	return nil, false
+}
+
+func (v *vVector) asPort() (*vPort, bool) {
+	return nil, false
+}
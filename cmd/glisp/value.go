@@ -12,7 +12,11 @@ type Value interface {
 	asReference() (Value, func(Value), bool)
 	setReference(Value) bool
 	asArray() ([]Value, bool)
-	asDict() (map[string]Value, bool)
+	asDict() (*dictMap, bool)
+	asChar() (rune, bool)
+	asKeyword() (string, bool)
+	asRegex() (*vRegex, bool)
+	asPort() (*vPort, bool)
 	
 	apply([]Value) (Value, error)
 	str() string
@@ -1,3 +1,3 @@
 module rpucella.net/go-lisp-command-language
 
-go 1.13
+go 1.16